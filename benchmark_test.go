@@ -1,6 +1,7 @@
 package runewidth
 
 import (
+	"strings"
 	"testing"
 	"unicode/utf8"
 )
@@ -33,6 +34,16 @@ func BenchmarkRuneWidthAll(b *testing.B) {
 func BenchmarkRuneWidth768(b *testing.B) {
 	benchSink = benchRuneWidth(b, 0, 0x300, 715)
 }
+func BenchmarkRuneWidthAllTerminal(b *testing.B) {
+	n := 0
+	c := NewConditionForTerminal("xterm")
+	for i := 0; i < b.N; i++ {
+		for r := rune(0); r <= utf8.MaxRune; r++ {
+			n += c.RuneWidth(r)
+		}
+	}
+	benchSink = n
+}
 
 //
 // String1Width - strings which consist of a single rune
@@ -61,15 +72,21 @@ func BenchmarkString1WidthAll(b *testing.B) {
 func BenchmarkString1Width768(b *testing.B) {
 	benchSink = benchString1Width(b, 0, 0x300, 715)
 }
+func BenchmarkString1WidthASCII(b *testing.B) {
+	benchSink = benchString1Width(b, 0x20, 0x7F, 95)
+}
+func BenchmarkStringWidthLatin1(b *testing.B) {
+	benchSink = benchStringWidth(b, benchLatin1)
+}
 
 //
 // tables
 //
-func benchTable(b *testing.B) int {
+func benchTable(b *testing.B, lookup func(rune) int) int {
 	n := 0
 	for i := 0; i < b.N; i++ {
 		for r := rune(0); r <= utf8.MaxRune; r++ {
-			if wcwidth9_width(r) >= -1 {
+			if lookup(r) >= -1 {
 				n++
 			}
 		}
@@ -78,5 +95,139 @@ func benchTable(b *testing.B) int {
 }
 
 func BenchmarkTableWcwidth9(b *testing.B) {
-	benchSink = benchTable(b)
+	benchSink = benchTable(b, wcwidth9_width)
+}
+
+func BenchmarkTableTrie(b *testing.B) {
+	benchSink = benchTable(b, trieWidth)
+}
+
+//
+// string functions
+//
+
+var (
+	benchASCII  = strings.Repeat("The quick brown fox jumps over the lazy dog. ", 20)
+	benchLatin1 = strings.Repeat("Größe, Façade, Naïveté, Smörgåsbord. ", 20)
+	benchCJK    = strings.Repeat("東京特許許可局局長はよく柿喰う客だ。", 20)
+	benchEmoji  = strings.Repeat("👩‍🍳🏳️‍🌈👨‍👨‍👧👁☆♥", 20)
+)
+
+func benchStringWidth(b *testing.B, s string) int {
+	return benchConditionStringWidth(b, NewCondition(), s)
+}
+func benchConditionStringWidth(b *testing.B, c *Condition, s string) int {
+	n := 0
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		n += c.StringWidth(s)
+	}
+	return n
+}
+func BenchmarkStringWidthASCII(b *testing.B) {
+	benchSink = benchStringWidth(b, benchASCII)
+}
+func BenchmarkStringWidthCJK(b *testing.B) {
+	benchSink = benchStringWidth(b, benchCJK)
+}
+func BenchmarkStringWidthEmoji(b *testing.B) {
+	benchSink = benchStringWidth(b, benchEmoji)
+}
+func BenchmarkStringWidthLatin1Terminal(b *testing.B) {
+	benchSink = benchConditionStringWidth(b, NewConditionForTerminal("xterm"), benchLatin1)
+}
+func BenchmarkStringWidthCJKTerminal(b *testing.B) {
+	benchSink = benchConditionStringWidth(b, NewConditionForTerminal("xterm"), benchCJK)
+}
+func BenchmarkStringWidthEmojiTerminal(b *testing.B) {
+	benchSink = benchConditionStringWidth(b, NewConditionForTerminal("xterm"), benchEmoji)
+}
+func BenchmarkStringWidthCJKCached(b *testing.B) {
+	c := NewCondition()
+	c.EnableCache(16)
+	benchSink = benchConditionStringWidth(b, c, benchCJK)
+}
+func BenchmarkStringWidthEmojiCached(b *testing.B) {
+	c := NewCondition()
+	c.EnableCache(16)
+	benchSink = benchConditionStringWidth(b, c, benchEmoji)
+}
+
+func benchTruncate(b *testing.B, s string) int {
+	n := 0
+	c := NewCondition()
+	w := c.StringWidth(s) / 2
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		n += len(c.Truncate(s, w, "..."))
+	}
+	return n
+}
+func BenchmarkTruncateASCII(b *testing.B) {
+	benchSink = benchTruncate(b, benchASCII)
+}
+func BenchmarkTruncateCJK(b *testing.B) {
+	benchSink = benchTruncate(b, benchCJK)
+}
+func BenchmarkTruncateEmoji(b *testing.B) {
+	benchSink = benchTruncate(b, benchEmoji)
+}
+
+func benchWrap(b *testing.B, s string) int {
+	n := 0
+	c := NewCondition()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		n += len(c.Wrap(s, 30))
+	}
+	return n
+}
+func BenchmarkWrapASCII(b *testing.B) {
+	benchSink = benchWrap(b, benchASCII)
+}
+func BenchmarkWrapCJK(b *testing.B) {
+	benchSink = benchWrap(b, benchCJK)
+}
+func BenchmarkWrapEmoji(b *testing.B) {
+	benchSink = benchWrap(b, benchEmoji)
+}
+
+func benchFillLeft(b *testing.B, s string) int {
+	n := 0
+	c := NewCondition()
+	w := c.StringWidth(s) + 40
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		n += len(c.FillLeft(s, w))
+	}
+	return n
+}
+func BenchmarkFillLeftASCII(b *testing.B) {
+	benchSink = benchFillLeft(b, benchASCII)
+}
+func BenchmarkFillLeftCJK(b *testing.B) {
+	benchSink = benchFillLeft(b, benchCJK)
+}
+func BenchmarkFillLeftEmoji(b *testing.B) {
+	benchSink = benchFillLeft(b, benchEmoji)
+}
+
+func benchFillRight(b *testing.B, s string) int {
+	n := 0
+	c := NewCondition()
+	w := c.StringWidth(s) + 40
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		n += len(c.FillRight(s, w))
+	}
+	return n
+}
+func BenchmarkFillRightASCII(b *testing.B) {
+	benchSink = benchFillRight(b, benchASCII)
+}
+func BenchmarkFillRightCJK(b *testing.B) {
+	benchSink = benchFillRight(b, benchCJK)
+}
+func BenchmarkFillRightEmoji(b *testing.B) {
+	benchSink = benchFillRight(b, benchEmoji)
 }
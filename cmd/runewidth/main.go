@@ -0,0 +1,224 @@
+// Command runewidth measures, truncates and wraps text the way go-runewidth
+// does, which helps to debug layout problems.
+//
+// Usage:
+//
+//	runewidth <command> [flags] [text ...]
+//
+// Each text argument is processed on its own. Without arguments, each line
+// of standard input is processed instead. The commands are:
+//
+//	width     print the width of the text
+//	truncate  truncate the text to -w cells, appending -tail
+//	wrap      wrap the text at -w cells
+//	fill      pad the text with spaces to -w cells (-left to pad on the left)
+//	runes     print the code point, width, class and East_Asian_Width of every
+//	          rune in the text
+//
+// Every command accepts the flags -terminal, -ambiguous, -unicode,
+// -emoji-presentation, -split-zwj, -normalize, -grapheme and -control,
+// which configure the Condition used for measuring. With -control, the
+// commands printing text show control characters in the chosen notation.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(fs *flag.FlagSet) func(c *runewidth.Condition, s string, w io.Writer)
+}
+
+var commands = []command{
+	{"width", "print the width of the text", func(fs *flag.FlagSet) func(*runewidth.Condition, string, io.Writer) {
+		return func(c *runewidth.Condition, s string, w io.Writer) {
+			fmt.Fprintln(w, c.StringWidth(s))
+		}
+	}},
+	{"truncate", "truncate the text to -w cells", func(fs *flag.FlagSet) func(*runewidth.Condition, string, io.Writer) {
+		width := fs.Int("w", 80, "width in cells")
+		tail := fs.String("tail", "...", "string appended to truncated text")
+		return func(c *runewidth.Condition, s string, w io.Writer) {
+			fmt.Fprintln(w, c.RenderControls(c.Truncate(s, *width, *tail)))
+		}
+	}},
+	{"wrap", "wrap the text at -w cells", func(fs *flag.FlagSet) func(*runewidth.Condition, string, io.Writer) {
+		width := fs.Int("w", 80, "width in cells")
+		return func(c *runewidth.Condition, s string, w io.Writer) {
+			// Render the lines one by one, so that the line breaks are not
+			// shown as controls.
+			for _, line := range strings.Split(c.Wrap(s, *width), "\n") {
+				fmt.Fprintln(w, c.RenderControls(line))
+			}
+		}
+	}},
+	{"fill", "pad the text with spaces to -w cells", func(fs *flag.FlagSet) func(*runewidth.Condition, string, io.Writer) {
+		width := fs.Int("w", 80, "width in cells")
+		left := fs.Bool("left", false, "pad on the left instead of the right")
+		return func(c *runewidth.Condition, s string, w io.Writer) {
+			if *left {
+				fmt.Fprintln(w, c.RenderControls(c.FillLeft(s, *width)))
+			} else {
+				fmt.Fprintln(w, c.RenderControls(c.FillRight(s, *width)))
+			}
+		}
+	}},
+	{"runes", "print the code point, width, class and East_Asian_Width of every rune", func(fs *flag.FlagSet) func(*runewidth.Condition, string, io.Writer) {
+		return func(c *runewidth.Condition, s string, w io.Writer) {
+			for _, r := range s {
+				fmt.Fprintf(w, "%U\t%q\t%d\t%v\t%v\n", r, r, c.RuneWidth(r), c.RuneClass(r), runewidth.EastAsianWidthOf(r))
+			}
+		}
+	}},
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: runewidth <command> [flags] [text ...]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-9s %s\n", cmd.name, cmd.usage)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'runewidth <command> -h' for the flags of a command.")
+}
+
+var graphemeWidths = map[string]runewidth.GraphemeWidthFunc{
+	"first":   runewidth.FirstRuneWidth,
+	"max":     runewidth.MaxRuneWidth,
+	"sum":     runewidth.SumRuneWidth,
+	"unicode": runewidth.UnicodeGraphemeWidth,
+}
+
+var controlNotations = map[string]runewidth.ControlNotation{
+	"none":  runewidth.ControlNone,
+	"caret": runewidth.ControlCaret,
+	"hex":   runewidth.ControlHex,
+}
+
+// conditionFlags defines the flags configuring the Condition on fs. The
+// returned function builds the Condition after fs has been parsed.
+func conditionFlags(fs *flag.FlagSet) func() (*runewidth.Condition, error) {
+	terminal := fs.String("terminal", "", "measure like the named terminal ("+strings.Join(runewidth.Terminals(), ", ")+"), or auto to detect it from the environment")
+	ambiguous := fs.Int("ambiguous", 0, "width of East Asian Ambiguous characters, 0 keeps the table's")
+	unicode := fs.String("unicode", "", "make wide characters newer than this Unicode version narrow")
+	emoji := fs.Bool("emoji-presentation", false, "make text-style emoji followed by VS16 wide")
+	splitZWJ := fs.Bool("split-zwj", false, "measure ZWJ sequences as the sum of their emoji")
+	normalize := fs.Bool("normalize", false, "measure grapheme clusters in Normalization Form C")
+	grapheme := fs.String("grapheme", "unicode", "width of a grapheme cluster: first, max, sum or unicode")
+	control := fs.String("control", "none", "notation of control characters: none, caret or hex")
+
+	return func() (*runewidth.Condition, error) {
+		c := runewidth.NewCondition()
+		var err error
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "terminal":
+				if *terminal == "auto" {
+					*c = *runewidth.NewConditionFromEnv(nil)
+					return
+				}
+				known := false
+				for _, name := range runewidth.Terminals() {
+					known = known || strings.EqualFold(name, *terminal)
+				}
+				if !known {
+					err = fmt.Errorf("unknown terminal %q", *terminal)
+				}
+				*c = *runewidth.NewConditionForTerminal(*terminal)
+			}
+		})
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "ambiguous":
+				c.AmbiguousWidth = *ambiguous
+			case "unicode":
+				v, e := runewidth.ParseUnicodeVersion(*unicode)
+				if e != nil {
+					err = e
+				}
+				c.UnicodeVersion = v
+			case "emoji-presentation":
+				c.EmojiPresentation = *emoji
+			case "split-zwj":
+				c.SplitZWJ = *splitZWJ
+			case "normalize":
+				c.Normalize = *normalize
+			case "grapheme":
+				f, ok := graphemeWidths[*grapheme]
+				if !ok {
+					err = fmt.Errorf("unknown grapheme width %q", *grapheme)
+				}
+				c.GraphemeWidth = f
+			case "control":
+				n, ok := controlNotations[*control]
+				if !ok {
+					err = fmt.Errorf("unknown control notation %q", *control)
+				}
+				c.ControlNotation = n
+			}
+		})
+		return c, err
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == args[0] {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(stderr, "runewidth: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("runewidth "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	f := cmd.run(fs)
+	condition := conditionFlags(fs)
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	c, err := condition()
+	if err != nil {
+		fmt.Fprintln(stderr, "runewidth:", err)
+		return 2
+	}
+
+	if fs.NArg() > 0 {
+		for _, s := range fs.Args() {
+			f(c, s, stdout)
+		}
+		return 0
+	}
+	scanner := bufio.NewScanner(stdin)
+	for scanner.Scan() {
+		f(c, scanner.Text(), stdout)
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(stderr, "runewidth:", err)
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
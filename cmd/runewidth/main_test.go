@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	var tests = []struct {
+		args  []string
+		stdin string
+		want  string
+	}{
+		{[]string{"width", "つのだ☆HIRO", "abc"}, "", "12\n3\n"},
+		{[]string{"width"}, "あいう\nabc\n", "6\n3\n"},
+		{[]string{"truncate", "-w", "7", "あいうえお"}, "", "あい...\n"},
+		{[]string{"truncate", "-w", "4", "-tail", "…", "あいうえお"}, "", "あ…\n"},
+		{[]string{"wrap", "-w", "4", "あいうえお"}, "", "あい\nうえ\nお\n"},
+		{[]string{"fill", "-w", "6", "あx"}, "", "あx   \n"},
+		{[]string{"fill", "-w", "6", "-left", "あx"}, "", "   あx\n"},
+		{[]string{"width", "-ambiguous", "1", "☆"}, "", "1\n"},
+		{[]string{"width", "-terminal", "xterm", "☆", "👩‍🍳"}, "", "1\n4\n"},
+		{[]string{"width", "-terminal", "xterm", "-ambiguous", "2", "☆"}, "", "2\n"},
+		{[]string{"width", "-unicode", "9.0", "🥲"}, "", "1\n"},
+		{[]string{"width", "-emoji-presentation", "☺\ufe0f"}, "", "2\n"},
+		{[]string{"width", "-split-zwj", "👩‍🍳"}, "", "4\n"},
+		{[]string{"width", "-grapheme", "first", "\u0915\u093e"}, "", "1\n"},
+		{[]string{"width", "-ambiguous", "2", "e\u0301"}, "", "1\n"},
+		{[]string{"width", "-ambiguous", "2", "-normalize", "e\u0301"}, "", "2\n"},
+		{[]string{"width", "-grapheme", "sum", "👩‍🍳"}, "", "4\n"},
+		{[]string{"width", "-control", "caret", "a\x00b"}, "", "4\n"},
+		{[]string{"fill", "-w", "6", "-control", "caret", "\x1b[m"}, "", "^[[m  \n"},
+		{[]string{"truncate", "-w", "5", "-control", "hex", "\x00\x01"}, "", "...\n"},
+		{[]string{"wrap", "-w", "4", "-control", "caret", "あいうえお"}, "", "あい\nうえ\nお\n"},
+		{[]string{"wrap", "-w", "3", "-control", "caret", "a\x1bbcdef"}, "", "a^[\nbcd\nef\n"},
+		{[]string{"wrap", "-w", "4", "-control", "hex", "ab\ncd\x7f"}, "", "ab\ncd\n\\x7f\n"},
+		{[]string{"runes", "aあ\x00"}, "", "U+0061\t'a'\t1\tnarrow\tNa\nU+3042\t'あ'\t2\twide\tW\nU+0000\t'\\x00'\t0\tcontrol\tN\n"},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if code := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr); code != 0 {
+			t.Errorf("run(%q) = %d, want 0: %s", tt.args, code, stderr.String())
+			continue
+		}
+		if got := stdout.String(); got != tt.want {
+			t.Errorf("run(%q) wrote %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestRunUsage(t *testing.T) {
+	for _, args := range [][]string{nil, {"nope"}, {"width", "-nope"}, {"width", "-terminal", "nope"}, {"width", "-unicode", "x"}, {"width", "-grapheme", "x"}, {"width", "-control", "x"}} {
+		var stdout, stderr bytes.Buffer
+		if code := run(args, strings.NewReader(""), &stdout, &stderr); code != 2 {
+			t.Errorf("run(%q) = %d, want 2", args, code)
+		}
+		if stderr.Len() == 0 {
+			t.Errorf("run(%q) wrote no usage", args)
+		}
+	}
+}
@@ -1,5 +1,8 @@
 module github.com/mattn/go-runewidth
 
-go 1.9
+go 1.17
 
-require github.com/rivo/uniseg v0.1.0
+require (
+	github.com/rivo/uniseg v0.1.0
+	golang.org/x/text v0.3.8
+)
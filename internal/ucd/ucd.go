@@ -0,0 +1,206 @@
+// Package ucd reads the Unicode Character Database in ICU's preparsed
+// format (ppucd.txt), which lists the properties of every code point in a
+// single file.
+//
+// See https://unicode-org.github.io/icu/design/props/ppucd.html
+package ucd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Props holds the property values of a code point by short property name.
+// Binary properties that are true have the value "Y".
+type Props map[string]string
+
+// Has reports whether the binary property name is true.
+func (p Props) Has(name string) bool {
+	return p[name] == "Y"
+}
+
+// Range is a range of code points sharing the same properties.
+type Range struct {
+	First, Last rune
+	Props       Props
+}
+
+// Database is the parsed content of a ppucd.txt file.
+type Database struct {
+	Version string  // Unicode version, such as "17.0.0"
+	Ranges  []Range // ranges covering U+0000 to U+10FFFF in order
+}
+
+// Lookup returns the properties of r.
+func (db *Database) Lookup(r rune) Props {
+	lo, hi := 0, len(db.Ranges)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		switch rg := db.Ranges[mid]; {
+		case rg.Last < r:
+			lo = mid + 1
+		case rg.First > r:
+			hi = mid
+		default:
+			return rg.Props
+		}
+	}
+	return nil
+}
+
+// Each calls f for the maximal ranges of code points over which the
+// property value returned by key does not change.
+func (db *Database) Each(key func(Props) string, f func(first, last rune, value string)) {
+	first, value := rune(-1), ""
+	for _, rg := range db.Ranges {
+		v := key(rg.Props)
+		if first >= 0 && v == value {
+			continue
+		}
+		if first >= 0 {
+			f(first, rg.First-1, value)
+		}
+		first, value = rg.First, v
+	}
+	if first >= 0 {
+		f(first, 0x10FFFF, value)
+	}
+}
+
+type parser struct {
+	db       Database
+	defaults Props
+	block    *Range
+	next     rune
+}
+
+// Parse reads a ppucd.txt file.
+func Parse(r io.Reader) (*Database, error) {
+	p := &parser{defaults: Props{}}
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	for n := 1; s.Scan(); n++ {
+		line := s.Text()
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if err := p.line(strings.Split(line, ";")); err != nil {
+			return nil, fmt.Errorf("ucd: line %d: %v", n, err)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	p.fill(0x10FFFF)
+	return &p.db, nil
+}
+
+func (p *parser) line(fields []string) error {
+	switch fields[0] {
+	case "ucd":
+		p.db.Version = fields[1]
+		return nil
+	case "defaults", "block", "cp", "unassigned":
+	default:
+		return nil
+	}
+
+	first, last, err := parseRange(fields[1])
+	if err != nil {
+		return err
+	}
+	switch fields[0] {
+	case "defaults":
+		p.defaults.set(fields[2:])
+	case "block":
+		p.fill(first - 1)
+		p.block = &Range{First: first, Last: last, Props: p.defaults.with(fields[2:])}
+	case "cp":
+		p.fill(first - 1)
+		props := p.defaults
+		if p.inBlock(first, last) {
+			props = p.block.Props
+		}
+		p.add(first, last, props.with(fields[2:]))
+	case "unassigned":
+		p.fill(first - 1)
+		props := p.defaults.with(nil)
+		if p.inBlock(first, last) {
+			props["blk"] = p.block.Props["blk"]
+		}
+		props.set(fields[2:])
+		p.add(first, last, props)
+	}
+	return nil
+}
+
+func (p *parser) inBlock(first, last rune) bool {
+	return p.block != nil && p.block.First <= first && last <= p.block.Last
+}
+
+// fill covers the code points from p.next to last which have no data line
+// with the properties of the current block or the defaults.
+func (p *parser) fill(last rune) {
+	for p.next <= last {
+		if p.inBlock(p.next, p.next) {
+			end := last
+			if p.block.Last < end {
+				end = p.block.Last
+			}
+			p.add(p.next, end, p.block.Props)
+			continue
+		}
+		end := last
+		if p.block != nil && p.next < p.block.First && p.block.First <= end {
+			end = p.block.First - 1
+		}
+		p.add(p.next, end, p.defaults)
+	}
+}
+
+func (p *parser) add(first, last rune, props Props) {
+	p.db.Ranges = append(p.db.Ranges, Range{First: first, Last: last, Props: props})
+	p.next = last + 1
+}
+
+// with returns a copy of p with the values of fields applied.
+func (p Props) with(fields []string) Props {
+	q := make(Props, len(p)+len(fields))
+	for k, v := range p {
+		q[k] = v
+	}
+	q.set(fields)
+	return q
+}
+
+func (p Props) set(fields []string) {
+	for _, f := range fields {
+		switch i := strings.IndexByte(f, '='); {
+		case i >= 0:
+			p[f[:i]] = f[i+1:]
+		case strings.HasPrefix(f, "-"):
+			delete(p, f[1:])
+		case f != "":
+			p[f] = "Y"
+		}
+	}
+}
+
+func parseRange(s string) (first, last rune, err error) {
+	lo, hi := s, s
+	if i := strings.Index(s, ".."); i >= 0 {
+		lo, hi = s[:i], s[i+2:]
+	}
+	f, err := strconv.ParseUint(lo, 16, 32)
+	if err != nil {
+		return 0, 0, err
+	}
+	l, err := strconv.ParseUint(hi, 16, 32)
+	if err != nil {
+		return 0, 0, err
+	}
+	return rune(f), rune(l), nil
+}
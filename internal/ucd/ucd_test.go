@@ -0,0 +1,80 @@
+package ucd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const sample = `# sample
+ucd;17.0.0
+property;Enumerated;ea;East_Asian_Width
+defaults;0000..10FFFF;age=NA;ea=N;gc=Cn
+block;0100..01FF;age=1.1;blk=B;ea=A;gc=Lo;Alpha
+cp;0120..0121;gc=Lu
+cp;0130;ea=W;-Alpha
+unassigned;01F0..01FF;ea=W
+cp;0300;age=2.0;gc=Mn
+`
+
+func TestParse(t *testing.T) {
+	db, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db.Version != "17.0.0" {
+		t.Errorf("Version = %q, want %q", db.Version, "17.0.0")
+	}
+
+	var tests = []struct {
+		r     rune
+		ea    string
+		gc    string
+		blk   string
+		alpha bool
+	}{
+		{0x0041, "N", "Cn", "", false},
+		{0x0100, "A", "Lo", "B", true},
+		{0x0121, "A", "Lu", "B", true},
+		{0x0130, "W", "Lo", "B", false},
+		{0x01EF, "A", "Lo", "B", true},
+		{0x01F0, "W", "Cn", "B", false},
+		{0x0200, "N", "Cn", "", false},
+		{0x0300, "N", "Mn", "", false},
+		{0x10FFFF, "N", "Cn", "", false},
+	}
+
+	for _, tt := range tests {
+		p := db.Lookup(tt.r)
+		if p["ea"] != tt.ea || p["gc"] != tt.gc || p["blk"] != tt.blk || p.Has("Alpha") != tt.alpha {
+			t.Errorf("Lookup(%U) = %v, want ea=%s gc=%s blk=%s Alpha=%v", tt.r, p, tt.ea, tt.gc, tt.blk, tt.alpha)
+		}
+	}
+
+	next := rune(0)
+	for _, rg := range db.Ranges {
+		if rg.First != next || rg.Last < rg.First {
+			t.Fatalf("range %U..%U does not start at %U", rg.First, rg.Last, next)
+		}
+		next = rg.Last + 1
+	}
+	if next != 0x110000 {
+		t.Errorf("ranges end at %U, want U+10FFFF", next-1)
+	}
+}
+
+func TestEach(t *testing.T) {
+	db, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	db.Each(func(p Props) string { return p["ea"] }, func(first, last rune, value string) {
+		got = append(got, fmt.Sprintf("%04X..%04X=%s", first, last, value))
+	})
+	want := "0000..00FF=N 0100..012F=A 0130..0130=W 0131..01EF=A 01F0..01FF=W 0200..10FFFF=N"
+	if s := strings.Join(got, " "); s != want {
+		t.Errorf("Each() = %q, want %q", s, want)
+	}
+}
@@ -1,7 +1,12 @@
 package runewidth
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/rivo/uniseg"
+	"golang.org/x/text/unicode/norm"
 )
 
 //go:generate make
@@ -19,6 +24,56 @@ type interval struct {
 
 type table []interval
 
+type runeRange struct {
+	first rune
+	last  rune
+}
+
+type rangeTable []runeRange
+
+func (t rangeTable) contains(r rune) bool {
+	lo, hi := 0, len(t)
+	for lo < hi {
+		mid := (lo + hi) >> 1
+		switch {
+		case t[mid].last < r:
+			lo = mid + 1
+		case t[mid].first > r:
+			hi = mid
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+type ageInterval struct {
+	first rune
+	last  rune
+	major uint8
+	minor uint8
+}
+
+type ageTable []ageInterval
+
+// age returns the Unicode version in which r was assigned, or the zero
+// version if r is not in t.
+func (t ageTable) age(r rune) UnicodeVersion {
+	lo, hi := 0, len(t)
+	for lo < hi {
+		mid := (lo + hi) >> 1
+		switch {
+		case t[mid].last < r:
+			lo = mid + 1
+		case t[mid].first > r:
+			hi = mid
+		default:
+			return UnicodeVersion{int(t[mid].major), int(t[mid].minor)}
+		}
+	}
+	return UnicodeVersion{}
+}
+
 func wcwidth9_width(r rune) int {
 	if r < wcwidth9_table[0].first {
 		return 1
@@ -43,7 +98,81 @@ func wcwidth9_width(r rune) int {
 }
 
 // Condition have flag EastAsianWidth whether the current locale is CJK or not.
-type Condition struct{}
+// The zero value measures with the widths of wcwidth9_table. Changing the
+// fields of a Condition with an enabled cache leaves stale widths in it.
+type Condition struct {
+	// AmbiguousWidth is the width of East Asian Ambiguous characters, such
+	// as '☆' and '│'. Zero keeps the width of the table.
+	AmbiguousWidth int
+
+	// UnicodeVersion makes wide characters assigned after that version one
+	// cell wide, as terminals with older tables do. The zero value keeps
+	// the width of the table.
+	UnicodeVersion UnicodeVersion
+
+	// EmojiPresentation makes a text-style emoji followed by VARIATION
+	// SELECTOR-16 (U+FE0F) or by an emoji modifier two cells wide, such as
+	// "☺\uFE0F" and "☝\U0001F3FB".
+	EmojiPresentation bool
+
+	// SplitZWJ measures a ZERO WIDTH JOINER sequence as the sum of the
+	// emoji it joins, for terminals which do not render it as one glyph.
+	SplitZWJ bool
+
+	// Normalize measures each grapheme cluster in Unicode Normalization
+	// Form C, so that canonically equivalent strings, such as "\u304C" and
+	// "\u304B\u3099" (が), have the same width in NFC and NFD.
+	Normalize bool
+
+	// ControlNotation measures C0 and C1 control characters and DEL as the
+	// notation RenderControls replaces them with, such as "^@" for NUL. The
+	// zero value, ControlNone, measures them as zero width.
+	ControlNotation ControlNotation
+
+	// InvalidUTF8 is how the string functions measure bytes which are not
+	// valid UTF-8. The zero value measures them as utf8.RuneError.
+	InvalidUTF8 InvalidUTF8
+
+	// GraphemeWidth returns the width of a grapheme cluster, or of each
+	// part of a ZWJ sequence with SplitZWJ. Nil means UnicodeGraphemeWidth.
+	// It is called for every cluster, including control characters and
+	// Hangul syllable blocks. UnicodeGraphemeWidth, FirstRuneWidth and
+	// MaxRuneWidth measure a syllable block as two cells; SumRuneWidth adds
+	// up its jamo.
+	GraphemeWidth GraphemeWidthFunc
+
+	cache *widthCache
+}
+
+// UnicodeVersion is a version of the Unicode Standard, such as 9.0.
+type UnicodeVersion struct {
+	Major, Minor int
+}
+
+// ParseUnicodeVersion parses a version such as "9.0" or "12.1.0".
+func ParseUnicodeVersion(s string) (UnicodeVersion, error) {
+	var v UnicodeVersion
+	f := strings.SplitN(s, ".", 3)
+	var err error
+	if v.Major, err = strconv.Atoi(f[0]); err != nil || v.Major <= 0 {
+		return UnicodeVersion{}, fmt.Errorf("runewidth: invalid Unicode version %q", s)
+	}
+	if len(f) > 1 {
+		if v.Minor, err = strconv.Atoi(f[1]); err != nil || v.Minor < 0 {
+			return UnicodeVersion{}, fmt.Errorf("runewidth: invalid Unicode version %q", s)
+		}
+	}
+	return v, nil
+}
+
+func (v UnicodeVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// Less reports whether v is older than w.
+func (v UnicodeVersion) Less(w UnicodeVersion) bool {
+	return v.Major < w.Major || v.Major == w.Major && v.Minor < w.Minor
+}
 
 // NewCondition return new instance of Condition which is current locale.
 func NewCondition() *Condition {
@@ -53,13 +182,32 @@ func NewCondition() *Condition {
 // RuneWidth returns the number of cells in r.
 // See http://www.unicode.org/reports/tr11/
 func (c *Condition) RuneWidth(r rune) int {
+	if c.ControlNotation != ControlNone && isControl(r) {
+		return c.ControlNotation.width(r)
+	}
+	if r >= 0 && r < rune(len(latinWidth)) {
+		return c.latinRuneWidth(r)
+	}
+	width := runeWidth(r)
+	switch {
+	case width == 0:
+		return 0
+	case c.AmbiguousWidth != 0 && isAmbiguous(r):
+		return c.AmbiguousWidth
+	case width == 2 && c.UnicodeVersion.Major != 0 && c.UnicodeVersion.Less(wideAges.age(r)):
+		return 1
+	}
+	return width
+}
+
+func runeWidth(r rune) int {
 	switch {
 	case r < 0x20 || r > 0x10FFFF:
 		return 0
 	case (r >= 0x7F && r <= 0x9F) || r == 0xAD: // nonprint
 		return 0
 	default:
-		if width := wcwidth9_width(r); width < 0 {
+		if width := trieWidth(r); width < 0 {
 			return 0
 		} else {
 			return width
@@ -67,22 +215,157 @@ func (c *Condition) RuneWidth(r rune) int {
 	}
 }
 
+// latinWidth holds the widths of U+0000 through U+02FF, the range before the
+// first combining mark.
+var latinWidth [0x300]int8
+
+func init() {
+	for r := range latinWidth {
+		latinWidth[r] = int8(runeWidth(rune(r)))
+	}
+}
+
+// latinRuneWidth returns the width of r below U+0300, which is East Asian
+// Ambiguous in places such as '§' and 'ü'.
+func (c *Condition) latinRuneWidth(r rune) int {
+	width := int(latinWidth[r])
+	if c.AmbiguousWidth != 0 && width != 0 && isAmbiguous(r) {
+		return c.AmbiguousWidth
+	}
+	return width
+}
+
+// latinStringWidth returns the width of s and true if s consists only of
+// runes below U+0300. Each of those runes is a grapheme cluster on its own
+// (CR LF is the only pair, and both are zero width), so the widths can simply
+// be summed up. A custom GraphemeWidth may measure them otherwise, so it is
+// called for every cluster.
+func (c *Condition) latinStringWidth(s string) (int, bool) {
+	if c.GraphemeWidth != nil {
+		return 0, false
+	}
+	for i := 0; i < len(s); i++ {
+		if b := s[i]; b < 0x20 || b >= 0x7F {
+			if c.ControlNotation != ControlNone {
+				return 0, false
+			}
+			return c.latinStringWidthFrom(s, i)
+		}
+	}
+	return len(s), true
+}
+
+func (c *Condition) latinStringWidthFrom(s string, i int) (int, bool) {
+	width := i
+	if c.AmbiguousWidth != 0 {
+		for _, r := range s[i:] {
+			if r >= rune(len(latinWidth)) {
+				return 0, false
+			}
+			width += c.latinRuneWidth(r)
+		}
+		return width, true
+	}
+	for _, r := range s[i:] {
+		if r >= rune(len(latinWidth)) {
+			return 0, false
+		}
+		width += int(latinWidth[r])
+	}
+	return width, true
+}
+
 // StringWidth return width as you can see
-func (c *Condition) StringWidth(s string) (width int) {
+func (c *Condition) StringWidth(s string) int {
+	if w, ok := c.latinStringWidth(s); ok {
+		return w
+	}
+	if c.cache == nil {
+		return c.graphemesWidth(s)
+	}
+	if w, ok := c.cache.get(s); ok {
+		return w
+	}
+	w := c.graphemesWidth(s)
+	c.cache.add(s, w)
+	return w
+}
+
+func (c *Condition) graphemesWidth(s string) (width int) {
 	g := uniseg.NewGraphemes(s)
 	for g.Next() {
-		var chWidth int
-		for _, r := range g.Runes() {
-			chWidth = c.RuneWidth(r)
-			if chWidth > 0 {
-				break // Our best guess at this point is to use the width of the first non-zero-width rune.
+		from, to := g.Positions()
+		width += c.stringClusterWidth(s[from:to], g.Runes())
+	}
+	return
+}
+
+// clusterWidth returns the width of the grapheme cluster rs.
+func (c *Condition) clusterWidth(rs []rune) int {
+	if c.Normalize {
+		rs = nfc(rs)
+	}
+	if c.SplitZWJ {
+		width := 0
+		for i := 0; i < len(rs); i++ {
+			if rs[i] != zwj {
+				continue
 			}
+			width += c.clusterWidth1(rs[:i])
+			rs = rs[i+1:]
+			i = -1
 		}
-		width += chWidth
+		return width + c.clusterWidth1(rs)
 	}
-	return
+	return c.clusterWidth1(rs)
 }
 
+func (c *Condition) clusterWidth1(rs []rune) int {
+	f := c.GraphemeWidth
+	if f == nil {
+		f = UnicodeGraphemeWidth
+	}
+	width := f(c, rs)
+	if width == 1 && c.EmojiPresentation {
+		for i, r := range rs {
+			if c.RuneWidth(r) > 0 {
+				if i+1 < len(rs) && (rs[i+1] == vs16 || isEmojiModifier(rs[i+1])) && emoji.contains(r) {
+					return 2
+				}
+				break
+			}
+		}
+	}
+	return width
+}
+
+// nfc returns rs in Normalization Form C. Runes below U+0300 are in NFC
+// and do not compose with each other, so clusters of them are returned as is.
+func nfc(rs []rune) []rune {
+	for _, r := range rs {
+		if r >= 0x300 {
+			s := string(rs)
+			if norm.NFC.IsNormalString(s) {
+				return rs
+			}
+			return []rune(norm.NFC.String(s))
+		}
+	}
+	return rs
+}
+
+// isEmojiModifier reports whether r is one of the skin tone modifiers
+// U+1F3FB..U+1F3FF.
+func isEmojiModifier(r rune) bool {
+	return r >= 0x1F3FB && r <= 0x1F3FF
+}
+
+const (
+	zwj  = '\u200D'
+	zwnj = '\u200C'
+	vs16 = '\uFE0F'
+)
+
 // Truncate return string truncated with w cells
 func (c *Condition) Truncate(s string, w int, tail string) string {
 	if c.StringWidth(s) <= w {
@@ -93,15 +376,10 @@ func (c *Condition) Truncate(s string, w int, tail string) string {
 	pos := len(s)
 	g := uniseg.NewGraphemes(s)
 	for g.Next() {
-		var chWidth int
-		for _, r := range g.Runes() {
-			chWidth = c.RuneWidth(r)
-			if chWidth > 0 {
-				break // See StringWidth() for details.
-			}
-		}
+		from, to := g.Positions()
+		chWidth := c.stringClusterWidth(s[from:to], g.Runes())
 		if width+chWidth > w {
-			pos, _ = g.Positions()
+			pos = from
 			break
 		}
 		width += chWidth
@@ -109,27 +387,30 @@ func (c *Condition) Truncate(s string, w int, tail string) string {
 	return s[:pos] + tail
 }
 
-// Wrap return string wrapped with w cells
+// Wrap return string wrapped with w cells. It breaks lines between grapheme
+// clusters, so a cluster wider than w gets a line of its own.
 func (c *Condition) Wrap(s string, w int) string {
+	var b strings.Builder
+	b.Grow(len(s))
 	width := 0
-	out := ""
-	for _, r := range []rune(s) {
-		cw := c.RuneWidth(r)
-		if r == '\n' {
-			out += string(r)
+	g := uniseg.NewGraphemes(s)
+	for g.Next() {
+		from, to := g.Positions()
+		cluster := s[from:to]
+		if cluster[len(cluster)-1] == '\n' {
+			b.WriteString(cluster)
 			width = 0
 			continue
-		} else if width+cw > w {
-			out += "\n"
+		}
+		cw := c.stringClusterWidth(cluster, g.Runes())
+		if width > 0 && width+cw > w {
+			b.WriteByte('\n')
 			width = 0
-			out += string(r)
-			width += cw
-			continue
 		}
-		out += string(r)
+		b.WriteString(cluster)
 		width += cw
 	}
-	return out
+	return b.String()
 }
 
 // FillLeft return string filled in left by spaces in w cells
@@ -137,11 +418,11 @@ func (c *Condition) FillLeft(s string, w int) string {
 	width := c.StringWidth(s)
 	count := w - width
 	if count > 0 {
-		b := make([]byte, count)
-		for i := range b {
-			b[i] = ' '
-		}
-		return string(b) + s
+		var b strings.Builder
+		b.Grow(count + len(s))
+		writeSpaces(&b, count)
+		b.WriteString(s)
+		return b.String()
 	}
 	return s
 }
@@ -151,15 +432,22 @@ func (c *Condition) FillRight(s string, w int) string {
 	width := c.StringWidth(s)
 	count := w - width
 	if count > 0 {
-		b := make([]byte, count)
-		for i := range b {
-			b[i] = ' '
-		}
-		return s + string(b)
+		var b strings.Builder
+		b.Grow(len(s) + count)
+		b.WriteString(s)
+		writeSpaces(&b, count)
+		return b.String()
 	}
 	return s
 }
 
+// writeSpaces writes n spaces to b.
+func writeSpaces(b *strings.Builder, n int) {
+	for i := 0; i < n; i++ {
+		b.WriteByte(' ')
+	}
+}
+
 // RuneWidth returns the number of cells in r.
 // See http://www.unicode.org/reports/tr11/
 func RuneWidth(r rune) int {
@@ -6,3 +6,9 @@ package runewidth
 func IsEastAsian() bool {
 	return false
 }
+
+// isEastAsianEnv reports whether the locale described by the environment
+// variables getenv looks up is CJK.
+func isEastAsianEnv(getenv func(string) string) bool {
+	return false
+}
@@ -0,0 +1,92 @@
+package runewidth
+
+import (
+	"container/list"
+	"sync"
+)
+
+// CacheStats holds the statistics of the width cache of a Condition.
+type CacheStats struct {
+	Hits   uint64 // lookups answered from the cache
+	Misses uint64 // lookups that had to measure the string
+	Len    int    // number of strings currently cached
+}
+
+// widthCache is a size-bounded LRU cache of string widths.
+type widthCache struct {
+	mu     sync.Mutex
+	size   int
+	ll     *list.List
+	items  map[string]*list.Element
+	hits   uint64
+	misses uint64
+}
+
+type cacheEntry struct {
+	s     string
+	width int
+}
+
+func newWidthCache(size int) *widthCache {
+	return &widthCache{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (wc *widthCache) get(s string) (int, bool) {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	if e, ok := wc.items[s]; ok {
+		wc.ll.MoveToFront(e)
+		wc.hits++
+		return e.Value.(*cacheEntry).width, true
+	}
+	wc.misses++
+	return 0, false
+}
+
+func (wc *widthCache) add(s string, width int) {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	if e, ok := wc.items[s]; ok {
+		wc.ll.MoveToFront(e)
+		e.Value.(*cacheEntry).width = width
+		return
+	}
+	wc.items[s] = wc.ll.PushFront(&cacheEntry{s: s, width: width})
+	if wc.ll.Len() > wc.size {
+		e := wc.ll.Back()
+		wc.ll.Remove(e)
+		delete(wc.items, e.Value.(*cacheEntry).s)
+	}
+}
+
+func (wc *widthCache) stats() CacheStats {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	return CacheStats{Hits: wc.hits, Misses: wc.misses, Len: wc.ll.Len()}
+}
+
+// EnableCache makes c remember the results of StringWidth for up to n
+// strings, evicting the least recently used one when full. Strings of
+// ASCII and Latin-1 characters are cheap to measure and are never cached.
+// The cache is safe for concurrent use, but EnableCache itself must not be
+// called while c is in use. An n <= 0 disables the cache.
+func (c *Condition) EnableCache(n int) {
+	if n <= 0 {
+		c.cache = nil
+		return
+	}
+	c.cache = newWidthCache(n)
+}
+
+// CacheStats returns the statistics of the cache enabled by EnableCache.
+// It returns zero statistics if the cache is disabled.
+func (c *Condition) CacheStats() CacheStats {
+	if c.cache == nil {
+		return CacheStats{}
+	}
+	return c.cache.stats()
+}
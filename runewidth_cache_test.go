@@ -0,0 +1,60 @@
+package runewidth
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	c := NewCondition()
+	c.EnableCache(2)
+
+	for _, s := range []string{"abc", "あい", "あい", "うえ", "おか", "あい"} {
+		c.StringWidth(s)
+	}
+	// "abc" is never cached; "あい" is evicted by "おか" before the last lookup.
+	want := CacheStats{Hits: 1, Misses: 4, Len: 2}
+	if got := c.CacheStats(); got != want {
+		t.Errorf("CacheStats() = %+v, want %+v", got, want)
+	}
+
+	for _, tt := range stringwidthtests {
+		for i := 0; i < 2; i++ {
+			if out := c.StringWidth(tt.in); out != tt.out {
+				t.Errorf("StringWidth(%q) = %d, want %d", tt.in, out, tt.out)
+			}
+		}
+	}
+
+	c.EnableCache(0)
+	c.StringWidth("あい")
+	if got := c.CacheStats(); got != (CacheStats{}) {
+		t.Errorf("CacheStats() = %+v after disabling, want zero", got)
+	}
+}
+
+func TestCacheConcurrent(t *testing.T) {
+	c := NewCondition()
+	c.EnableCache(4)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				for _, tt := range stringwidthtests {
+					if out := c.StringWidth(tt.in); out != tt.out {
+						t.Errorf("StringWidth(%q) = %d, want %d", tt.in, out, tt.out)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := c.CacheStats()
+	if total := stats.Hits + stats.Misses; total != 8*100*uint64(len(stringwidthtests)) {
+		t.Errorf("Hits+Misses = %d, want %d", total, 8*100*len(stringwidthtests))
+	}
+}
@@ -0,0 +1,76 @@
+package runewidth
+
+import (
+	"strconv"
+)
+
+// Class is the kind of a rune as far as its display is concerned.
+type Class int
+
+const (
+	// ClassControl is a C0 or C1 control character or DEL, or a value
+	// which is not a code point.
+	ClassControl Class = iota
+	// ClassFormat is an invisible character which the table marks as non
+	// printable, such as SOFT HYPHEN, ZERO WIDTH SPACE, the bidirectional
+	// controls U+202A..U+202E, ZERO WIDTH NO-BREAK SPACE and surrogates.
+	ClassFormat
+	// ClassZeroWidth is a combining mark or another character that does
+	// not take a cell of its own.
+	ClassZeroWidth
+	// ClassNarrow is a character one cell wide.
+	ClassNarrow
+	// ClassWide is a character two cells wide, such as a CJK ideograph.
+	ClassWide
+	// ClassAmbiguous is an East Asian Ambiguous character, whose width
+	// depends on the context.
+	ClassAmbiguous
+	// ClassEmoji is a character with emoji presentation by default.
+	ClassEmoji
+)
+
+var classNames = [...]string{
+	ClassControl:   "control",
+	ClassFormat:    "format",
+	ClassZeroWidth: "zero-width",
+	ClassNarrow:    "narrow",
+	ClassWide:      "wide",
+	ClassAmbiguous: "ambiguous",
+	ClassEmoji:     "emoji",
+}
+
+func (cl Class) String() string {
+	if cl >= 0 && int(cl) < len(classNames) {
+		return classNames[cl]
+	}
+	return "Class(" + strconv.Itoa(int(cl)) + ")"
+}
+
+// RuneClass returns the class of r. Unlike RuneWidth, which returns 0 for
+// all of them, it tells control characters, invisible format characters
+// and combining marks apart.
+func (c *Condition) RuneClass(r rune) Class {
+	switch {
+	case r < 0x20 || r > 0x10FFFF || (r >= 0x7F && r <= 0x9F):
+		return ClassControl
+	case r == 0xAD || trieWidth(r) < 0:
+		return ClassFormat
+	}
+	width := c.RuneWidth(r)
+	switch {
+	case width == 0:
+		return ClassZeroWidth
+	case emojiPresentation.contains(r):
+		return ClassEmoji
+	case isAmbiguous(r):
+		return ClassAmbiguous
+	case width == 2:
+		return ClassWide
+	}
+	return ClassNarrow
+}
+
+// RuneClass returns the class of r.
+func RuneClass(r rune) Class {
+	return DefaultCondition.RuneClass(r)
+}
@@ -0,0 +1,58 @@
+package runewidth
+
+import (
+	"testing"
+)
+
+func TestRuneClass(t *testing.T) {
+	var tests = []struct {
+		in   rune
+		want Class
+	}{
+		{'\x00', ClassControl},
+		{'\x1b', ClassControl},
+		{'\x7f', ClassControl},
+		{'\u0085', ClassControl},
+		{-1, ClassControl},
+		{0x110000, ClassControl},
+		{'\u00ad', ClassFormat},
+		{'\u070f', ClassFormat},
+		{'\u200b', ClassFormat},
+		{'\u200f', ClassFormat},
+		{'\u2028', ClassFormat},
+		{'\u202e', ClassFormat},
+		{'\ufeff', ClassFormat},
+		{0xD800, ClassFormat},
+		{'\u0300', ClassZeroWidth},
+		{'\u200d', ClassFormat},
+		{'\u3099', ClassZeroWidth},
+		{'\ufe0f', ClassZeroWidth},
+		{'a', ClassNarrow},
+		{'ｾ', ClassNarrow},
+		{'☺', ClassNarrow},
+		{'世', ClassWide},
+		{'あ', ClassWide},
+		{'☆', ClassAmbiguous},
+		{'│', ClassAmbiguous},
+		{'ü', ClassAmbiguous},
+		{'👁', ClassWide},
+		{'\U0001F600', ClassEmoji},
+		{'⌚', ClassEmoji},
+		{'\U0001F1EF', ClassEmoji},
+	}
+
+	for _, tt := range tests {
+		if got := RuneClass(tt.in); got != tt.want {
+			t.Errorf("RuneClass(%U) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestClassString(t *testing.T) {
+	if got, want := ClassEmoji.String(), "emoji"; got != want {
+		t.Errorf("ClassEmoji.String() = %q, want %q", got, want)
+	}
+	if got, want := Class(42).String(), "Class(42)"; got != want {
+		t.Errorf("Class(42).String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,413 @@
+package runewidth
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/mattn/go-runewidth/internal/ucd"
+	"golang.org/x/text/width"
+)
+
+// The conformance tests check the widths against the Unicode data files in
+// testdata: ppucd.txt, which holds the properties of EastAsianWidth.txt,
+// DerivedGeneralCategory.txt and emoji-data.txt for every code point,
+// emoji-test.txt and GraphemeBreakTest.txt. TestConformanceUCD checks
+// ppucd.txt against other copies of those properties.
+
+// tableVersion is the Unicode version of wcwidth9_table. Code points
+// assigned later are not checked.
+var tableVersion = UnicodeVersion{13, 0}
+
+// conformanceRules allow the widths of whole classes of code points to
+// differ from ucdWidth.
+var conformanceRules = []struct {
+	reason string
+	allow  func(p ucd.Props, width int) bool
+}{
+	{"East Asian Ambiguous characters are one or two cells wide depending on the context (UAX #11)", func(p ucd.Props, width int) bool {
+		return p["ea"] == "A" && (width == 1 || width == 2)
+	}},
+	{"the table makes emoji with text presentation by default two cells wide", func(p ucd.Props, width int) bool {
+		return p.Has("Emoji") && !p.Has("EPres") && width == 2
+	}},
+}
+
+// conformanceExceptions are the ranges of code points whose width differs
+// from ucdWidth on purpose.
+var conformanceExceptions = []struct {
+	first, last rune
+	reason      string
+}{
+	{0x115F, 0x115F, "the table measures HANGUL CHOSEONG FILLER as a leading consonant"},
+	{0x1160, 0x11FF, "the table measures medial vowels and final consonants as one cell, syllable blocks are measured as a whole"},
+	{0x1734, 0x1734, "HANUNOO SIGN PAMUDPOD is a nonspacing mark in the table's version of Unicode"},
+	{0x2630, 0x2637, "East_Asian_Width of the trigrams changed to Wide in Unicode 16.0"},
+	{0x268A, 0x268F, "East_Asian_Width of the monograms and digrams changed to Wide in Unicode 16.0"},
+	{0x3164, 0x3164, "the table draws HANGUL FILLER, which is default ignorable"},
+	{0x4DC0, 0x4DFF, "East_Asian_Width of the hexagrams changed to Wide in Unicode 16.0"},
+	{0xD7B0, 0xD7C6, "the table measures medial vowels as one cell, syllable blocks are measured as a whole"},
+	{0xD7CB, 0xD7FB, "the table measures final consonants as one cell, syllable blocks are measured as a whole"},
+	{0xFFA0, 0xFFA0, "the table draws HALFWIDTH HANGUL FILLER, which is default ignorable"},
+	{0x1171E, 0x1171E, "AHOM CONSONANT SIGN MEDIAL RA is a nonspacing mark in the table's version of Unicode"},
+	{0x1D300, 0x1D356, "East_Asian_Width of the Tai Xuan Jing symbols changed to Wide in Unicode 16.0"},
+	{0x1D360, 0x1D376, "East_Asian_Width of the counting rod numerals changed to Wide in Unicode 16.0"},
+	{0x1F3FB, 0x1F3FF, "emoji modifiers are measured as part of the emoji they modify"},
+}
+
+// graphemeBreakExceptions are the lines of GraphemeBreakTest.txt for which
+// measuring the whole string differs from measuring its clusters.
+var graphemeBreakExceptions = map[string]string{
+	"\u2701\u200d\u2701": "uniseg does not break between the pictographs, unlike the Unicode 17.0 rules",
+}
+
+// ucdWidth returns the width of a code point with the properties p: zero for
+// controls, marks, format and default ignorable characters and the medial
+// vowels and final consonants of conjoining jamo, two for East Asian Wide and
+// Fullwidth characters and emoji with emoji presentation, and one otherwise.
+func ucdWidth(p ucd.Props) int {
+	switch p["gc"] {
+	case "Cc", "Cs", "Mn", "Me", "Cf", "Zl", "Zp":
+		return 0
+	}
+	switch {
+	case p.Has("DI") || p["GCB"] == "V" || p["GCB"] == "T":
+		return 0
+	case p["ea"] == "W" || p["ea"] == "F" || p.Has("EPres"):
+		return 2
+	}
+	return 1
+}
+
+func loadUCD(t *testing.T) *ucd.Database {
+	f, err := os.Open("testdata/ppucd.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	db, err := ucd.Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestConformanceCodePoints(t *testing.T) {
+	db := loadUCD(t)
+	used := make([]bool, len(conformanceExceptions))
+
+	type deviation struct {
+		first, last rune
+		got, want   int
+	}
+	var deviations []deviation
+	for _, rg := range db.Ranges {
+		p := rg.Props
+		if p["gc"] == "Cn" {
+			continue
+		}
+		v, err := ParseUnicodeVersion(p["age"])
+		if err != nil {
+			t.Fatalf("U+%04X: %v", rg.First, err)
+		}
+		if tableVersion.Less(v) {
+			continue
+		}
+		want := ucdWidth(p)
+	next:
+		for r := rg.First; r <= rg.Last; r++ {
+			got := RuneWidth(r)
+			if got == want {
+				continue
+			}
+			for _, rule := range conformanceRules {
+				if rule.allow(p, got) {
+					continue next
+				}
+			}
+			for i, e := range conformanceExceptions {
+				if e.first <= r && r <= e.last {
+					used[i] = true
+					continue next
+				}
+			}
+			if n := len(deviations); n > 0 && deviations[n-1].last == r-1 && deviations[n-1].got == got && deviations[n-1].want == want {
+				deviations[n-1].last = r
+				continue
+			}
+			deviations = append(deviations, deviation{r, r, got, want})
+		}
+	}
+
+	for _, d := range deviations {
+		t.Errorf("RuneWidth(U+%04X..U+%04X %s) = %d, want %d", d.first, d.last, db.Lookup(d.first)["na"], d.got, d.want)
+	}
+	for i, e := range conformanceExceptions {
+		if !used[i] {
+			t.Errorf("exception U+%04X..U+%04X (%s) is no longer needed", e.first, e.last, e.reason)
+		}
+	}
+}
+
+// TestConformanceUCD checks the properties in ppucd.txt the other tests rely
+// on against other sources of the same data files: the categories of the
+// unicode package, which come from UnicodeData.txt, the East Asian widths of
+// golang.org/x/text/width, which come from EastAsianWidth.txt of Unicode
+// 13.0, and the presentations emoji-test.txt lists for the emoji.
+func TestConformanceUCD(t *testing.T) {
+	db := loadUCD(t)
+
+	if unicode.Version != UnicodeDataVersion {
+		t.Logf("skipping General_Category: the unicode package has Unicode %s, ppucd.txt %s", unicode.Version, UnicodeDataVersion)
+	} else {
+		db.Each(func(p ucd.Props) string { return p["gc"] }, func(first, last rune, gc string) {
+			table := unicode.Categories[gc]
+			for r := first; r <= last; r++ {
+				if table == nil || !unicode.Is(table, r) {
+					t.Errorf("U+%04X: gc is %s in ppucd.txt, not in the unicode package", r, gc)
+				}
+			}
+		})
+	}
+
+	eaVersion := UnicodeVersion{13, 0}
+	if v, err := ParseUnicodeVersion(width.UnicodeVersion); err != nil || v != eaVersion {
+		t.Fatalf("golang.org/x/text/width has Unicode %s, want %s", width.UnicodeVersion, eaVersion)
+	}
+	for _, rg := range db.Ranges {
+		p := rg.Props
+		switch p["gc"] {
+		case "Cn", "Cs":
+			continue
+		}
+		v, err := ParseUnicodeVersion(p["age"])
+		if err != nil {
+			t.Fatalf("U+%04X: %v", rg.First, err)
+		}
+		if eaVersion.Less(v) {
+			continue
+		}
+	next:
+		for r := rg.First; r <= rg.Last; r++ {
+			got := widthKindNames[width.LookupRune(r).Kind()]
+			if got == p["ea"] {
+				continue
+			}
+			for _, c := range eastAsianWidthChanges {
+				if c.first <= r && r <= c.last && c.from == got && c.to == p["ea"] {
+					continue next
+				}
+			}
+			t.Errorf("U+%04X: ea is %s in ppucd.txt, %s in golang.org/x/text/width", r, p["ea"], got)
+		}
+	}
+
+	readEmojiTest(t, func(s, codePoints string) {
+		rs := []rune(s)
+		switch {
+		case len(rs) == 1:
+			if p := db.Lookup(rs[0]); !p.Has("Emoji") || !p.Has("EPres") {
+				t.Errorf("%s is an emoji with emoji presentation in emoji-test.txt, not in ppucd.txt", codePoints)
+			}
+		case len(rs) == 2 && rs[1] == vs16:
+			if p := db.Lookup(rs[0]); !p.Has("Emoji") || p.Has("EPres") {
+				t.Errorf("%s is an emoji with text presentation in emoji-test.txt, not in ppucd.txt", codePoints)
+			}
+		}
+	})
+}
+
+// widthKindNames are the short names of the values of East_Asian_Width by
+// the kinds of golang.org/x/text/width.
+var widthKindNames = map[width.Kind]string{
+	width.Neutral:            "N",
+	width.EastAsianAmbiguous: "A",
+	width.EastAsianWide:      "W",
+	width.EastAsianNarrow:    "Na",
+	width.EastAsianFullwidth: "F",
+	width.EastAsianHalfwidth: "H",
+}
+
+// eastAsianWidthChanges are the East_Asian_Width values which changed
+// between Unicode 13.0 and the version of ppucd.txt.
+var eastAsianWidthChanges = []struct {
+	first, last rune
+	from, to    string
+}{
+	{0x2630, 0x2637, "N", "W"},   // trigrams, Unicode 16.0
+	{0x268A, 0x268F, "N", "W"},   // monograms and digrams, Unicode 16.0
+	{0x4DC0, 0x4DFF, "N", "W"},   // hexagrams, Unicode 16.0
+	{0x1D300, 0x1D356, "N", "W"}, // Tai Xuan Jing symbols, Unicode 16.0
+	{0x1D360, 0x1D376, "N", "W"}, // counting rod numerals, Unicode 16.0
+}
+
+// readTestData calls f with the fields of each line of a Unicode data file
+// in testdata, separated by sep, and the comment of the line.
+func readTestData(t *testing.T, name string, sep string, f func(fields []string, comment string)) {
+	file, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	s := bufio.NewScanner(file)
+	for s.Scan() {
+		line, comment := s.Text(), ""
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line, comment = line[:i], line[i+1:]
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, sep)
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		f(fields, comment)
+	}
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func parseCodePoints(t *testing.T, s string) string {
+	var b strings.Builder
+	for _, h := range strings.Fields(s) {
+		r, err := strconv.ParseUint(h, 16, 32)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.WriteRune(rune(r))
+	}
+	return b.String()
+}
+
+// emojiExceptions allow fully-qualified emoji sequences to be measured
+// other than two cells wide with DefaultCondition.
+var emojiExceptions = []struct {
+	reason string
+	allow  func(db *ucd.Database, rs []rune, width int) bool
+}{
+	{"a text-style emoji followed by VARIATION SELECTOR-16 keeps the width of its base unless EmojiPresentation is set", func(db *ucd.Database, rs []rune, width int) bool {
+		return len(rs) > 1 && rs[1] == vs16 && isTextEmoji(db, rs[0]) && width == RuneWidth(rs[0])
+	}},
+	{"a text-style emoji followed by an emoji modifier keeps the width of its base unless EmojiPresentation is set", func(db *ucd.Database, rs []rune, width int) bool {
+		return len(rs) > 1 && rs[1] >= 0x1F3FB && rs[1] <= 0x1F3FF && isTextEmoji(db, rs[0]) && width == RuneWidth(rs[0])
+	}},
+}
+
+// isTextEmoji reports whether r is an emoji with text presentation by
+// default.
+func isTextEmoji(db *ucd.Database, r rune) bool {
+	p := db.Lookup(r)
+	return p.Has("Emoji") && !p.Has("EPres")
+}
+
+// readEmojiTest calls f with the fully-qualified emoji sequences of
+// emoji-test.txt which were added in tableVersion or before, as the
+// E<version> in the comment of each line tells.
+func readEmojiTest(t *testing.T, f func(s, codePoints string)) {
+	n := 0
+	readTestData(t, "emoji-test.txt", ";", func(fields []string, comment string) {
+		if len(fields) != 2 || fields[1] != "fully-qualified" {
+			return
+		}
+		c := strings.Fields(comment)
+		if len(c) < 2 || !strings.HasPrefix(c[1], "E") {
+			t.Fatalf("no emoji version in %q", comment)
+		}
+		v, err := emojiVersion(c[1][1:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tableVersion.Less(v) {
+			return
+		}
+		n++
+		f(parseCodePoints(t, fields[0]), fields[0])
+	})
+	if n == 0 {
+		t.Fatal("no fully-qualified emoji in emoji-test.txt")
+	}
+}
+
+// emojiVersion parses the version of an emoji, which is 0.6 or 0.7 for the
+// emoji which came from the Japanese carriers and the Unicode version since
+// Emoji 11.0.
+func emojiVersion(s string) (UnicodeVersion, error) {
+	var v UnicodeVersion
+	f := strings.SplitN(s, ".", 2)
+	var err error
+	if v.Major, err = strconv.Atoi(f[0]); err != nil {
+		return v, err
+	}
+	if len(f) > 1 {
+		if v.Minor, err = strconv.Atoi(f[1]); err != nil {
+			return v, err
+		}
+	}
+	return v, nil
+}
+
+func TestConformanceEmoji(t *testing.T) {
+	db := loadUCD(t)
+	used := make([]bool, len(emojiExceptions))
+	readEmojiTest(t, func(s, codePoints string) {
+		got := StringWidth(s)
+		if got == 2 {
+			return
+		}
+		for i, e := range emojiExceptions {
+			if e.allow(db, []rune(s), got) {
+				used[i] = true
+				return
+			}
+		}
+		t.Errorf("StringWidth(%q) = %d, want 2 (%s)", s, got, codePoints)
+	})
+	for i, e := range emojiExceptions {
+		if !used[i] {
+			t.Errorf("exception (%s) is no longer needed", e.reason)
+		}
+	}
+}
+
+func TestConformanceEmojiPresentation(t *testing.T) {
+	c := &Condition{EmojiPresentation: true}
+	readEmojiTest(t, func(s, codePoints string) {
+		if got := c.StringWidth(s); got != 2 {
+			t.Errorf("StringWidth(%q) = %d, want 2 (%s)", s, got, codePoints)
+		}
+	})
+}
+
+func TestConformanceGraphemeBreak(t *testing.T) {
+	used := map[string]bool{}
+	readTestData(t, "GraphemeBreakTest.txt", "÷", func(fields []string, _ string) {
+		var clusters []string
+		for _, f := range fields {
+			if f != "" {
+				clusters = append(clusters, parseCodePoints(t, strings.Replace(f, "×", " ", -1)))
+			}
+		}
+		s := strings.Join(clusters, "")
+		want := 0
+		for _, cluster := range clusters {
+			want += StringWidth(cluster)
+		}
+		if got := StringWidth(s); got != want {
+			if _, ok := graphemeBreakExceptions[s]; ok {
+				used[s] = true
+				return
+			}
+			t.Errorf("StringWidth(%q) = %d, want %d, the sum of its clusters %q", s, got, want, clusters)
+		}
+	})
+	for s, reason := range graphemeBreakExceptions {
+		if !used[s] {
+			t.Errorf("exception %q (%s) is no longer needed", s, reason)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package runewidth
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// ControlNotation is the notation control characters are displayed in.
+type ControlNotation int
+
+const (
+	// ControlNone leaves control characters as they are. They are zero
+	// width.
+	ControlNone ControlNotation = iota
+	// ControlCaret is the notation of cat -v: "^@" through "^_" for the C0
+	// controls, "^?" for DEL and "M-^@" through "M-^_" for the C1 controls.
+	ControlCaret
+	// ControlHex writes the code point as an escape sequence, "\x00"
+	// through "\x9f".
+	ControlHex
+)
+
+// isControl reports whether r is a C0 or C1 control character or DEL.
+func isControl(r rune) bool {
+	return (r >= 0 && r < 0x20) || (r >= 0x7F && r <= 0x9F)
+}
+
+// isControlCluster reports whether the grapheme cluster rs is a control
+// character, which is a cluster on its own, or CR LF. The built-in
+// GraphemeWidthFuncs measure it rune by rune, as RenderControls writes the
+// notation of each rune.
+func isControlCluster(rs []rune) bool {
+	return len(rs) > 0 && isControl(rs[0])
+}
+
+// notation returns the control character r in n.
+func (n ControlNotation) notation(r rune) string {
+	switch n {
+	case ControlCaret:
+		if r >= 0x80 {
+			return "M-^" + string(r-0x80+'@')
+		}
+		return "^" + string(r^0x40)
+	case ControlHex:
+		const hex = "0123456789abcdef"
+		return `\x` + string(hex[r>>4]) + string(hex[r&0xF])
+	}
+	return string(r)
+}
+
+// width returns the number of cells in the notation of the control
+// character r.
+func (n ControlNotation) width(r rune) int {
+	switch {
+	case n == ControlCaret && r >= 0x80:
+		return 4
+	case n == ControlCaret:
+		return 2
+	case n == ControlHex:
+		return 4
+	}
+	return 0
+}
+
+// RenderControls replaces the control characters in s with their notation
+// in c.ControlNotation, so that they can be seen. The width of the result is
+// the StringWidth of s.
+func (c *Condition) RenderControls(s string) string {
+	if c.ControlNotation == ControlNone || strings.IndexFunc(s, isControl) < 0 {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s) + 8)
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		if isControl(r) {
+			b.WriteString(c.ControlNotation.notation(r))
+		} else {
+			b.WriteString(s[:size])
+		}
+		s = s[size:]
+	}
+	return b.String()
+}
+
+// RenderControls replaces the control characters in s with their notation
+// in DefaultCondition.ControlNotation.
+func RenderControls(s string) string {
+	return DefaultCondition.RenderControls(s)
+}
@@ -0,0 +1,61 @@
+package runewidth
+
+import (
+	"testing"
+)
+
+var controltests = []struct {
+	in    string
+	caret string
+	hex   string
+}{
+	{"", "", ""},
+	{"abc", "abc", "abc"},
+	{"\x00", "^@", `\x00`},
+	{"a\tb", "a^Ib", `a\x09b`},
+	{"\x1b[0m", "^[[0m", `\x1b[0m`},
+	{"\x1f\x7f", "^_^?", `\x1f\x7f`},
+	{"\r\n", "^M^J", `\x0d\x0a`},
+	{"\u0080\u0085\u009f", "M-^@M-^EM-^_", `\x80\x85\x9f`},
+	{"\u00a0", "\u00a0", "\u00a0"},
+	{"あ\x00い", "あ^@い", `あ\x00い`},
+	{"e\x07\u0301", "e^G\u0301", `e\x07` + "\u0301"},
+}
+
+func TestRenderControls(t *testing.T) {
+	for _, tt := range controltests {
+		for _, n := range []struct {
+			notation ControlNotation
+			want     string
+		}{
+			{ControlNone, tt.in},
+			{ControlCaret, tt.caret},
+			{ControlHex, tt.hex},
+		} {
+			c := &Condition{ControlNotation: n.notation}
+			out := c.RenderControls(tt.in)
+			if out != n.want {
+				t.Errorf("RenderControls(%q) with notation %d = %q, want %q", tt.in, n.notation, out, n.want)
+			}
+			if got, want := c.StringWidth(tt.in), StringWidth(out); got != want {
+				t.Errorf("StringWidth(%q) with notation %d = %d, want %d", tt.in, n.notation, got, want)
+			}
+		}
+	}
+}
+
+func TestControlNotationWidth(t *testing.T) {
+	c := &Condition{ControlNotation: ControlCaret, AmbiguousWidth: 2}
+	if got := c.StringWidth("\x00☆"); got != 4 {
+		t.Errorf("StringWidth(%q) = %d, want %d", "\x00☆", got, 4)
+	}
+	if got := c.Truncate("\x01\x02\x03", 5, ""); got != "\x01\x02" {
+		t.Errorf("Truncate(%q, 5) = %q, want %q", "\x01\x02\x03", got, "\x01\x02")
+	}
+	if got := c.FillRight("\x00", 4); got != "\x00  " {
+		t.Errorf("FillRight(%q, 4) = %q, want %q", "\x00", got, "\x00  ")
+	}
+	if got := c.Wcwidth('\x00'); got != 0 {
+		t.Errorf("Wcwidth(%q) = %d, want %d", '\x00', got, 0)
+	}
+}
@@ -0,0 +1,107 @@
+package runewidth
+
+import (
+	"strconv"
+)
+
+// EastAsianWidth is a value of the East_Asian_Width property defined in
+// Unicode Standard Annex #11. See http://www.unicode.org/reports/tr11/
+type EastAsianWidth int
+
+const (
+	EastAsianNeutral   EastAsianWidth = iota // N
+	EastAsianAmbiguous                       // A
+	EastAsianHalfwidth                       // H
+	EastAsianWide                            // W
+	EastAsianFullwidth                       // F
+	EastAsianNarrow                          // Na
+)
+
+var eastAsianWidthNames = [...]string{
+	EastAsianNeutral:   "N",
+	EastAsianAmbiguous: "A",
+	EastAsianHalfwidth: "H",
+	EastAsianWide:      "W",
+	EastAsianFullwidth: "F",
+	EastAsianNarrow:    "Na",
+}
+
+// String returns the short name of the property value, such as "Na".
+func (e EastAsianWidth) String() string {
+	if e >= 0 && int(e) < len(eastAsianWidthNames) {
+		return eastAsianWidthNames[e]
+	}
+	return "EastAsianWidth(" + strconv.Itoa(int(e)) + ")"
+}
+
+type eastAsianWidthInterval struct {
+	first rune
+	last  rune
+	ea    EastAsianWidth
+}
+
+type eastAsianWidthTable []eastAsianWidthInterval
+
+// EastAsianWidthOf returns the East_Asian_Width property of r as defined by
+// the Unicode Character Database the package was generated from, see
+// UnicodeDataVersion. Unlike RuneWidth it does not depend on a Condition.
+func EastAsianWidthOf(r rune) EastAsianWidth {
+	t := eastAsianWidths
+	lo, hi := 0, len(t)
+	for lo < hi {
+		mid := (lo + hi) >> 1
+		switch {
+		case t[mid].last < r:
+			lo = mid + 1
+		case t[mid].first > r:
+			hi = mid
+		default:
+			return t[mid].ea
+		}
+	}
+	return EastAsianNeutral
+}
+
+// bmpAmbiguous is a bitmap of the East Asian Ambiguous code points below
+// U+10000, and ambiguousBlocks one of the blocks of 256 code points above
+// which have any, so that RuneWidth does not have to search
+// eastAsianWidths for every rune when AmbiguousWidth is set.
+var (
+	bmpAmbiguous    [0x10000 / 64]uint64
+	ambiguousBlocks [(0x110000 - 0x10000) / 256 / 64]uint64
+)
+
+func init() {
+	for _, iv := range eastAsianWidths {
+		if iv.ea != EastAsianAmbiguous {
+			continue
+		}
+		for r := iv.first; r <= iv.last; r++ {
+			if r < 0x10000 {
+				bmpAmbiguous[r>>6] |= 1 << uint(r&63)
+			} else {
+				b := (r - 0x10000) >> 8
+				ambiguousBlocks[b>>6] |= 1 << uint(b&63)
+			}
+		}
+	}
+}
+
+// isAmbiguous reports whether the East_Asian_Width of r is Ambiguous.
+func isAmbiguous(r rune) bool {
+	switch {
+	case r < 0 || r > 0x10FFFF:
+		return false
+	case r < 0x10000:
+		return bmpAmbiguous[r>>6]&(1<<uint(r&63)) != 0
+	}
+	if b := (r - 0x10000) >> 8; ambiguousBlocks[b>>6]&(1<<uint(b&63)) == 0 {
+		return false
+	}
+	return EastAsianWidthOf(r) == EastAsianAmbiguous
+}
+
+// UnicodeDataVersion is the version of the Unicode Character Database which
+// EastAsianWidthOf and RuneClass follow. The widths of RuneWidth come from
+// wcwidth9_table, which may follow a different version.
+const UnicodeDataVersion = ucdVersion
@@ -0,0 +1,94 @@
+package runewidth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEastAsianWidthOf(t *testing.T) {
+	var tests = []struct {
+		in   rune
+		want EastAsianWidth
+	}{
+		{'\x00', EastAsianNeutral},
+		{'a', EastAsianNarrow},
+		{'⟦', EastAsianNarrow},
+		{'ｾ', EastAsianHalfwidth},
+		{'₩', EastAsianHalfwidth},
+		{'Ａ', EastAsianFullwidth},
+		{'\u3000', EastAsianFullwidth},
+		{'あ', EastAsianWide},
+		{'世', EastAsianWide},
+		{'\U0001F600', EastAsianWide},
+		{'\U00020000', EastAsianWide},
+		{'\U0003FFFD', EastAsianWide},
+		{'☆', EastAsianAmbiguous},
+		{'│', EastAsianAmbiguous},
+		{'é', EastAsianAmbiguous},
+		{'\u0300', EastAsianAmbiguous},
+		{'\ue000', EastAsianAmbiguous},
+		{'\ufffd', EastAsianAmbiguous},
+		{'☺', EastAsianNeutral},
+		{'ʼ', EastAsianNeutral},
+		{'\U000E0001', EastAsianNeutral},
+		{-1, EastAsianNeutral},
+		{0x110000, EastAsianNeutral},
+	}
+
+	for _, tt := range tests {
+		if got := EastAsianWidthOf(tt.in); got != tt.want {
+			t.Errorf("EastAsianWidthOf(%U) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEastAsianWidthString(t *testing.T) {
+	var tests = []struct {
+		in   EastAsianWidth
+		want string
+	}{
+		{EastAsianNeutral, "N"},
+		{EastAsianAmbiguous, "A"},
+		{EastAsianHalfwidth, "H"},
+		{EastAsianWide, "W"},
+		{EastAsianFullwidth, "F"},
+		{EastAsianNarrow, "Na"},
+		{EastAsianWidth(-1), "EastAsianWidth(-1)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("EastAsianWidth(%d).String() = %q, want %q", int(tt.in), got, tt.want)
+		}
+	}
+}
+
+func TestIsAmbiguous(t *testing.T) {
+	for r := rune(-1); r <= 0x110000; r++ {
+		if got, want := isAmbiguous(r), EastAsianWidthOf(r) == EastAsianAmbiguous; got != want {
+			t.Errorf("isAmbiguous(%U) = %v, want %v", r, got, want)
+		}
+	}
+}
+
+func TestLatinAmbiguousWidth(t *testing.T) {
+	for _, aw := range []int{1, 2} {
+		c := &Condition{AmbiguousWidth: aw}
+		var b strings.Builder
+		want := 0
+		for r := rune(0x20); r < 0x300; r++ {
+			w := runeWidth(r)
+			if w != 0 && EastAsianWidthOf(r) == EastAsianAmbiguous {
+				w = aw
+			}
+			if got := c.RuneWidth(r); got != w {
+				t.Errorf("AmbiguousWidth %d: RuneWidth(%U) = %d, want %d", aw, r, got, w)
+			}
+			b.WriteRune(r)
+			want += w
+		}
+		if got := c.StringWidth(b.String()); got != want {
+			t.Errorf("AmbiguousWidth %d: StringWidth(U+0020..U+02FF) = %d, want %d", aw, got, want)
+		}
+	}
+}
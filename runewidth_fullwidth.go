@@ -0,0 +1,194 @@
+package runewidth
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	dakuten             = '\u3099' // COMBINING KATAKANA-HIRAGANA VOICED SOUND MARK
+	handakuten          = '\u309A' // COMBINING KATAKANA-HIRAGANA SEMI-VOICED SOUND MARK
+	halfwidthDakuten    = 'ﾞ'
+	halfwidthHandakuten = 'ﾟ'
+)
+
+// halfwidthKatakana maps the halfwidth forms U+FF61..U+FF9F to their
+// fullwidth counterparts.
+var halfwidthKatakana = [...]rune{
+	'。', '「', '」', '、', '・', 'ヲ', 'ァ', 'ィ', // ｡｢｣､･ｦｧｨ
+	'ゥ', 'ェ', 'ォ', 'ャ', 'ュ', 'ョ', 'ッ', 'ー', // ｩｪｫｬｭｮｯｰ
+	'ア', 'イ', 'ウ', 'エ', 'オ', 'カ', 'キ', 'ク', // ｱｲｳｴｵｶｷｸ
+	'ケ', 'コ', 'サ', 'シ', 'ス', 'セ', 'ソ', 'タ', // ｹｺｻｼｽｾｿﾀ
+	'チ', 'ツ', 'テ', 'ト', 'ナ', 'ニ', 'ヌ', 'ネ', // ﾁﾂﾃﾄﾅﾆﾇﾈ
+	'ノ', 'ハ', 'ヒ', 'フ', 'ヘ', 'ホ', 'マ', 'ミ', // ﾉﾊﾋﾌﾍﾎﾏﾐ
+	'ム', 'メ', 'モ', 'ヤ', 'ユ', 'ヨ', 'ラ', 'リ', // ﾑﾒﾓﾔﾕﾖﾗﾘ
+	'ル', 'レ', 'ロ', 'ワ', 'ン', '゛', '゜', // ﾙﾚﾛﾜﾝﾞﾟ
+}
+
+// fullwidthSymbols maps the fullwidth forms of the symbols outside ASCII to
+// their narrow counterparts.
+var fullwidthSymbols = map[rune]rune{
+	'｟': '⦅', // FULLWIDTH LEFT WHITE PARENTHESIS
+	'｠': '⦆', // FULLWIDTH RIGHT WHITE PARENTHESIS
+	'￠': '¢', // FULLWIDTH CENT SIGN
+	'￡': '£', // FULLWIDTH POUND SIGN
+	'￢': '¬', // FULLWIDTH NOT SIGN
+	'￣': '¯', // FULLWIDTH MACRON
+	'￤': '¦', // FULLWIDTH BROKEN BAR
+	'￥': '¥', // FULLWIDTH YEN SIGN
+	'￦': '₩', // FULLWIDTH WON SIGN
+}
+
+// toHalfwidth maps fullwidth runes to their halfwidth forms. It is filled
+// in init from the tables above.
+var toHalfwidth = map[rune]rune{}
+
+// toFullwidth maps halfwidth runes, other than ASCII, to their fullwidth
+// forms.
+var toFullwidth = map[rune]rune{}
+
+func init() {
+	for i, r := range halfwidthKatakana {
+		toFullwidth[0xFF61+rune(i)] = r
+		toHalfwidth[r] = 0xFF61 + rune(i)
+	}
+	toHalfwidth[dakuten] = halfwidthDakuten
+	toHalfwidth[handakuten] = halfwidthHandakuten
+	for full, half := range fullwidthSymbols {
+		toFullwidth[half] = full
+		toHalfwidth[full] = half
+	}
+}
+
+// fullwidth returns the fullwidth form of r, or r if it has none.
+func fullwidth(r rune) rune {
+	switch {
+	case r == ' ':
+		return '　'
+	case '!' <= r && r <= '~':
+		return r + 0xFEE0
+	}
+	if f, ok := toFullwidth[r]; ok {
+		return f
+	}
+	return r
+}
+
+// halfwidth returns the halfwidth form of r, which may be followed by a
+// halfwidth (semi-)voiced sound mark, or "" if r has none.
+func halfwidth(r rune) string {
+	switch {
+	case r == '　':
+		return " "
+	case '！' <= r && r <= '～':
+		return string(r - 0xFEE0)
+	}
+	if h, ok := toHalfwidth[r]; ok {
+		return string(h)
+	}
+	// A voiced katakana, such as GA, decomposes into its base and a
+	// combining sound mark, which both have halfwidth forms.
+	if d := []rune(norm.NFD.String(string(r))); len(d) == 2 {
+		b, ok1 := toHalfwidth[d[0]]
+		m, ok2 := toHalfwidth[d[1]]
+		if ok1 && ok2 && (d[1] == dakuten || d[1] == handakuten) {
+			return string([]rune{b, m})
+		}
+	}
+	return ""
+}
+
+// compose returns the precomposed katakana for base followed by the
+// combining sound mark m, if there is one.
+func compose(base, m rune) (rune, bool) {
+	c := []rune(norm.NFC.String(string([]rune{base, m})))
+	if len(c) == 1 {
+		return c[0], true
+	}
+	return 0, false
+}
+
+// ToFullwidth converts the printable ASCII characters and the space, the
+// halfwidth katakana U+FF61..U+FF9F and the narrow symbols which have a
+// fullwidth form, such as the YEN SIGN, to their fullwidth forms. A
+// halfwidth katakana followed by a halfwidth (semi-)voiced sound mark is
+// composed into a single character, such as "ｶﾞ" into "ガ".
+func ToFullwidth(s string) string {
+	rs := []rune(s)
+	var b strings.Builder
+	b.Grow(len(s) * 3)
+	for i := 0; i < len(rs); i++ {
+		r := fullwidth(rs[i])
+		if i+1 < len(rs) && r != rs[i] {
+			var m rune
+			switch rs[i+1] {
+			case halfwidthDakuten:
+				m = dakuten
+			case halfwidthHandakuten:
+				m = handakuten
+			}
+			if m != 0 {
+				if c, ok := compose(r, m); ok {
+					r = c
+					i++
+				}
+			}
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// ToHalfwidth converts the fullwidth forms U+FF01..U+FF5E, the ideographic
+// space, the katakana and the symbols which have a halfwidth form to it.
+// A voiced katakana is decomposed into two halfwidth characters, such as
+// "ガ" into "ｶﾞ". Other characters, such as hiragana, are kept as is.
+func ToHalfwidth(s string) string {
+	return mapHalfwidth(s, func(_, half string) string {
+		return half
+	})
+}
+
+// Narrowest returns the variant of s which takes the fewest cells: every
+// character with a halfwidth form, as converted by ToHalfwidth, is
+// replaced by it if that makes it narrower.
+func (c *Condition) Narrowest(s string) string {
+	return mapHalfwidth(s, func(full, half string) string {
+		if c.StringWidth(half) < c.StringWidth(full) {
+			return half
+		}
+		return full
+	})
+}
+
+// mapHalfwidth replaces every character of s with a halfwidth form with
+// the result of f.
+func mapHalfwidth(s string, f func(full, half string) string) string {
+	rs := []rune(s)
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(rs); i++ {
+		r, full := rs[i], string(rs[i])
+		// Compose a katakana followed by a combining sound mark, so that the
+		// pair is replaced as a whole.
+		if i+1 < len(rs) && (rs[i+1] == dakuten || rs[i+1] == handakuten) {
+			if c, ok := compose(r, rs[i+1]); ok {
+				r, full = c, full+string(rs[i+1])
+				i++
+			}
+		}
+		if half := halfwidth(r); half != "" {
+			b.WriteString(f(full, half))
+		} else {
+			b.WriteString(full)
+		}
+	}
+	return b.String()
+}
+
+// Narrowest returns the variant of s which takes the fewest cells with
+// DefaultCondition.
+func Narrowest(s string) string {
+	return DefaultCondition.Narrowest(s)
+}
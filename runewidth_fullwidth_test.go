@@ -0,0 +1,88 @@
+package runewidth
+
+import (
+	"testing"
+)
+
+func TestToFullwidth(t *testing.T) {
+	var tests = []struct {
+		in, out string
+	}{
+		{"", ""},
+		{"abc XYZ 012", "ａｂｃ　ＸＹＺ　０１２"},
+		{"!~", "！～"},
+		{"ｾｶｲ", "セカイ"},
+		{"ｶﾞｷﾞｸﾞ", "ガギグ"},
+		{"ﾊﾟﾋﾟﾌﾟﾍﾟﾎﾟ", "パピプペポ"},
+		{"ｳﾞｦﾞ", "ヴヺ"},
+		{"ｱﾞ", "ア゛"},
+		{"ﾞ", "゛"},
+		{"aﾞ", "ａ゛"},
+		{"｡｢ｰ｣､･", "。「ー」、・"},
+		{"¥100", "￥１００"},
+		{"ガ世界あ", "ガ世界あ"},
+		{"é", "é"},
+	}
+
+	for _, tt := range tests {
+		if out := ToFullwidth(tt.in); out != tt.out {
+			t.Errorf("ToFullwidth(%q) = %q, want %q", tt.in, out, tt.out)
+		}
+	}
+}
+
+func TestToHalfwidth(t *testing.T) {
+	var tests = []struct {
+		in, out string
+	}{
+		{"", ""},
+		{"ａｂｃ　ＸＹＺ　０１２", "abc XYZ 012"},
+		{"！～", "!~"},
+		{"セカイ", "ｾｶｲ"},
+		{"ガギグ", "ｶﾞｷﾞｸﾞ"},
+		{"パピプペポ", "ﾊﾟﾋﾟﾌﾟﾍﾟﾎﾟ"},
+		{"ヴヺ", "ｳﾞｦﾞ"},
+		{"ガ", "ｶﾞ"},
+		{"ア゛", "ｱﾞ"},
+		{"。「ー」、・", "｡｢ｰ｣､･"},
+		{"￥１００", "¥100"},
+		{"ヰヵ世界あがab", "ヰヵ世界あがab"},
+	}
+
+	for _, tt := range tests {
+		if out := ToHalfwidth(tt.in); out != tt.out {
+			t.Errorf("ToHalfwidth(%q) = %q, want %q", tt.in, out, tt.out)
+		}
+	}
+}
+
+func TestFullwidthRoundTrip(t *testing.T) {
+	for _, s := range []string{"ｾｶｲ ﾉ ﾊﾟｿｺﾝ", "Hello, World!", "ｳﾞｧｲｵﾘﾝ ¥980"} {
+		if out := ToHalfwidth(ToFullwidth(s)); out != s {
+			t.Errorf("ToHalfwidth(ToFullwidth(%q)) = %q", s, out)
+		}
+	}
+}
+
+func TestNarrowest(t *testing.T) {
+	var tests = []struct {
+		in, out string
+	}{
+		{"", ""},
+		{"セカイ", "ｾｶｲ"},
+		{"ＡＢＣ　１２３", "ABC 123"},
+		{"ガ１", "ガ1"},
+		{"パソコン", "パｿｺﾝ"},
+		{"世界", "世界"},
+		{"abc", "abc"},
+	}
+
+	for _, tt := range tests {
+		if out := Narrowest(tt.in); out != tt.out {
+			t.Errorf("Narrowest(%q) = %q, want %q", tt.in, out, tt.out)
+		}
+		if w, want := StringWidth(Narrowest(tt.in)), StringWidth(tt.in); w > want {
+			t.Errorf("StringWidth(Narrowest(%q)) = %d, want <= %d", tt.in, w, want)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// +build go1.18
+
+package runewidth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rivo/uniseg"
+)
+
+// fuzzConditions are the conditions the string functions are fuzzed with.
+var fuzzConditions = []*Condition{
+	{},
+	{AmbiguousWidth: 2},
+	{UnicodeVersion: UnicodeVersion{9, 0}},
+	{EmojiPresentation: true},
+	{SplitZWJ: true},
+	{Normalize: true},
+	{ControlNotation: ControlCaret},
+	{InvalidUTF8: InvalidAsReplacement},
+	{InvalidUTF8: InvalidAsZero},
+	{GraphemeWidth: SumRuneWidth},
+}
+
+// fuzzTails are the tails Truncate is fuzzed with. A tail starting with a
+// combining mark or a joiner could merge with the end of the text, so
+// arbitrary tails are not used.
+var fuzzTails = []string{"", "...", "…", "~", "→", "＞"}
+
+func fuzzSeeds(f *testing.F) {
+	for _, s := range []string{
+		"",
+		"abc",
+		"つのだ☆HIRO",
+		"東京特許許可局局長はよく柿喰う客だ\n123456789012345678901234567890",
+		"👩\u200d🍳👨\u200d👩\u200d👧\u200d👦",
+		"☺\ufe0f🇯🇵🇺🇸",
+		"e\u0301क\u093e",
+		"각한국어",
+		"\x00\x1b[31mred\x1b[0m\r\n",
+		"abc\xff\xe3\x81",
+		"ｾｶｲ\u200bﾊﾟｿｺﾝ",
+	} {
+		f.Add(s, 10, uint8(0))
+		f.Add(s, 3, uint8(1))
+		f.Add(s, 1, uint8(2))
+	}
+}
+
+func FuzzTruncate(f *testing.F) {
+	fuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, s string, w int, n uint8) {
+		if w < 0 || w > 200 {
+			return
+		}
+		tail := fuzzTails[int(n)%len(fuzzTails)]
+		for _, c := range fuzzConditions {
+			out := c.Truncate(s, w, tail)
+			if out == s {
+				if c.StringWidth(s) > w {
+					t.Errorf("%+v: Truncate(%q, %d, %q) = %q, but it is %d wide", c, s, w, tail, out, c.StringWidth(s))
+				}
+				continue
+			}
+			if !strings.HasSuffix(out, tail) || !strings.HasPrefix(s, out[:len(out)-len(tail)]) {
+				t.Errorf("%+v: Truncate(%q, %d, %q) = %q, not a prefix plus tail", c, s, w, tail, out)
+				continue
+			}
+			if c.StringWidth(tail) <= w && c.StringWidth(out) > w {
+				t.Errorf("%+v: StringWidth(Truncate(%q, %d, %q)) = %d, want <= %d", c, s, w, tail, c.StringWidth(out), w)
+			}
+		}
+	})
+}
+
+func FuzzWrap(f *testing.F) {
+	fuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, s string, w int, _ uint8) {
+		if w < 0 || w > 200 {
+			return
+		}
+		for _, c := range fuzzConditions {
+			out := c.Wrap(s, w)
+			if strings.Replace(out, "\n", "", -1) != strings.Replace(s, "\n", "", -1) {
+				t.Errorf("%+v: Wrap(%q, %d) = %q changes the text", c, s, w, out)
+				continue
+			}
+			lines := strings.Split(out, "\n")
+			for i, line := range lines {
+				// CR LF is a line break.
+				if i+1 < len(lines) {
+					line = strings.TrimSuffix(line, "\r")
+				}
+				if c.StringWidth(line) <= w {
+					continue
+				}
+				// Only a line with a single cluster which is not zero width may
+				// be wider than w.
+				wide := 0
+				g := uniseg.NewGraphemes(line)
+				for g.Next() {
+					if from, to := g.Positions(); c.StringWidth(line[from:to]) > 0 {
+						wide++
+					}
+				}
+				if wide > 1 {
+					t.Errorf("%+v: Wrap(%q, %d) has the line %q of width %d", c, s, w, line, c.StringWidth(line))
+				}
+			}
+		}
+	})
+}
+
+func FuzzFill(f *testing.F) {
+	fuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, s string, w int, _ uint8) {
+		if w < 0 || w > 200 {
+			return
+		}
+		for _, c := range fuzzConditions {
+			want := c.StringWidth(s)
+			if want < w {
+				want = w
+			}
+			left, right := c.FillLeft(s, w), c.FillRight(s, w)
+			if got := c.StringWidth(left); got != want {
+				t.Errorf("%+v: StringWidth(FillLeft(%q, %d)) = %d, want %d", c, s, w, got, want)
+			}
+			if got := c.StringWidth(right); got != want {
+				t.Errorf("%+v: StringWidth(FillRight(%q, %d)) = %d, want %d", c, s, w, got, want)
+			}
+			if !strings.HasSuffix(left, s) || strings.Trim(left[:len(left)-len(s)], " ") != "" {
+				t.Errorf("%+v: FillLeft(%q, %d) = %q, not spaces plus s", c, s, w, left)
+			}
+			if !strings.HasPrefix(right, s) || strings.Trim(right[len(s):], " ") != "" {
+				t.Errorf("%+v: FillRight(%q, %d) = %q, not s plus spaces", c, s, w, right)
+			}
+		}
+	})
+}
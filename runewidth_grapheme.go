@@ -0,0 +1,74 @@
+package runewidth
+
+// GraphemeWidthFunc returns the number of cells in the grapheme cluster
+// runes, measuring single runes with c.RuneWidth.
+type GraphemeWidthFunc func(c *Condition, runes []rune) int
+
+// FirstRuneWidth returns the width of the first rune of the cluster which
+// is not zero width. It ignores the runes following it, so it under-counts
+// spacing marks such as the vowel sign in "का".
+func FirstRuneWidth(c *Condition, runes []rune) int {
+	if isControlCluster(runes) {
+		return SumRuneWidth(c, runes)
+	}
+	for _, r := range runes {
+		if width := c.RuneWidth(r); width > 0 {
+			return width
+		}
+	}
+	return 0
+}
+
+// MaxRuneWidth returns the width of the widest rune of the cluster.
+func MaxRuneWidth(c *Condition, runes []rune) int {
+	if isControlCluster(runes) {
+		return SumRuneWidth(c, runes)
+	}
+	max := 0
+	for _, r := range runes {
+		if width := c.RuneWidth(r); width > max {
+			max = width
+		}
+	}
+	return max
+}
+
+// SumRuneWidth returns the sum of the widths of the runes of the cluster,
+// as wcswidth(3) and terminals measuring rune by rune do. It measures the
+// vowels and trailing consonants of a Hangul syllable block spelled with
+// conjoining jamo as one cell each.
+func SumRuneWidth(c *Condition, runes []rune) int {
+	width := 0
+	for _, r := range runes {
+		width += c.RuneWidth(r)
+	}
+	return width
+}
+
+// UnicodeGraphemeWidth is the default GraphemeWidthFunc. The first rune of
+// the cluster which is not zero width is its base. A wide base, such as an
+// ideograph, a Hangul syllable or an emoji, makes the cluster two cells wide
+// whatever follows. A narrow base adds up with the spacing marks following
+// it, as in "का", up to a ZERO WIDTH JOINER, which joins the next rune into
+// the glyph of the base. A Hangul syllable block is two cells wide, whether
+// precomposed or spelled with conjoining jamo.
+func UnicodeGraphemeWidth(c *Condition, runes []rune) int {
+	switch {
+	case isHangulSyllable(runes):
+		return 2
+	case isControlCluster(runes):
+		return SumRuneWidth(c, runes)
+	}
+	width := 0
+	for _, r := range runes {
+		w := c.RuneWidth(r)
+		switch {
+		case width == 0 && w == 2:
+			return 2
+		case r == zwj && width > 0:
+			return width
+		}
+		width += w
+	}
+	return width
+}
@@ -0,0 +1,96 @@
+package runewidth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGraphemeWidth(t *testing.T) {
+	var tests = []struct {
+		in                       string
+		first, max, sum, unicode int
+	}{
+		{"a", 1, 1, 1, 1},
+		{"e\u0301", 1, 1, 1, 1},
+		{"\u0915\u093e", 1, 1, 2, 2},                     // DEVANAGARI KA, AA
+		{"\u0e2a\u0e33", 1, 1, 2, 2},                     // THAI SO SUA, SARA AM
+		{"\u1100\u1161\u11a8", 2, 2, 4, 2},               // HANGUL L, V, T
+		{"\U0001F469\u200d\U0001F373", 2, 2, 4, 2},       // WOMAN, ZWJ, COOKING
+		{"\u200d\U0001F373", 2, 2, 2, 2},                 // ZWJ, COOKING
+		{"\U0001F1EF\U0001F1F5", 2, 2, 4, 2},             // REGIONAL INDICATOR J, P
+		{"1\ufe0f\u20e3", 1, 1, 1, 1},                    // KEYCAP 1
+		{"\u0915\u093e\u0915\u093e", 2, 2, 4, 4},         // two clusters
+		{"\U0001F3F3\ufe0f\u200d\U0001F308", 2, 2, 4, 2}, // RAINBOW FLAG
+	}
+
+	for _, tt := range tests {
+		for _, st := range []struct {
+			name string
+			f    GraphemeWidthFunc
+			want int
+		}{
+			{"FirstRuneWidth", FirstRuneWidth, tt.first},
+			{"MaxRuneWidth", MaxRuneWidth, tt.max},
+			{"SumRuneWidth", SumRuneWidth, tt.sum},
+			{"UnicodeGraphemeWidth", UnicodeGraphemeWidth, tt.unicode},
+			{"nil", nil, tt.unicode},
+		} {
+			c := &Condition{GraphemeWidth: st.f}
+			if got := c.StringWidth(tt.in); got != st.want {
+				t.Errorf("StringWidth(%q) with %s = %d, want %d", tt.in, st.name, got, st.want)
+			}
+		}
+	}
+}
+
+func TestGraphemeWidthCustom(t *testing.T) {
+	var got []string
+	c := &Condition{
+		ControlNotation: ControlCaret,
+		GraphemeWidth: func(c *Condition, runes []rune) int {
+			got = append(got, string(runes))
+			return 1
+		},
+	}
+	s := "\u1100\u1161\u11a8\r\n\uac00"
+	if w := c.StringWidth(s); w != 3 {
+		t.Errorf("StringWidth(%q) with a custom GraphemeWidth = %d, want 3", s, w)
+	}
+	want := []string{"\u1100\u1161\u11a8", "\r\n", "\uac00"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GraphemeWidth called with %q, want %q", got, want)
+	}
+}
+
+func TestGraphemeWidthTruncate(t *testing.T) {
+	s := "\u0915\u093e\u0915\u093e\u0915\u093e"
+
+	if got, want := (&Condition{GraphemeWidth: FirstRuneWidth}).Truncate(s, 2, ""), "\u0915\u093e\u0915\u093e"; got != want {
+		t.Errorf("Truncate(%q) with FirstRuneWidth = %q, want %q", s, got, want)
+	}
+	if got, want := NewCondition().Truncate(s, 2, ""), "\u0915\u093e"; got != want {
+		t.Errorf("Truncate(%q) = %q, want %q", s, got, want)
+	}
+}
+
+func TestGraphemeWidthLatin(t *testing.T) {
+	c := &Condition{GraphemeWidth: func(*Condition, []rune) int { return 3 }}
+	for _, tt := range []struct {
+		in   string
+		want int
+	}{
+		{"ab", 6},
+		{"a\u00e9", 6},
+		{"a\u3042", 6},
+	} {
+		if got := c.StringWidth(tt.in); got != tt.want {
+			t.Errorf("StringWidth(%q) with a custom GraphemeWidth = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+	for _, f := range []GraphemeWidthFunc{FirstRuneWidth, MaxRuneWidth, SumRuneWidth, UnicodeGraphemeWidth} {
+		c := &Condition{GraphemeWidth: f}
+		if got := c.StringWidth("a\u00e9"); got != 2 {
+			t.Errorf("StringWidth(%q) = %d, want 2", "a\u00e9", got)
+		}
+	}
+}
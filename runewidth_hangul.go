@@ -0,0 +1,51 @@
+package runewidth
+
+// Hangul_Syllable_Type values of conjoining jamo and precomposed syllables.
+const (
+	hangulNone = iota
+	hangulL    // leading consonant (choseong)
+	hangulV    // vowel (jungseong)
+	hangulT    // trailing consonant (jongseong)
+	hangulLV   // precomposed syllable without trailing consonant
+	hangulLVT  // precomposed syllable with trailing consonant
+)
+
+// hangulSyllableType returns the Hangul_Syllable_Type of r.
+func hangulSyllableType(r rune) int {
+	switch {
+	case r < 0x1100:
+		return hangulNone
+	case r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return hangulL
+	case r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return hangulV
+	case r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return hangulT
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return hangulLV
+		}
+		return hangulLVT
+	}
+	return hangulNone
+}
+
+// isHangulSyllable reports whether the grapheme cluster rs is a Hangul
+// syllable block, either precomposed or spelled with conjoining jamo as in
+// U+1100 U+1161 U+11A8, possibly followed by combining marks. A block starts
+// with a leading consonant or a precomposed syllable; a vowel or trailing
+// consonant on its own, even followed by a mark, is not one.
+//
+// The table gives the vowels and trailing consonants a width of one, so
+// measuring the jamo of a block one by one does not match the two cells a
+// terminal draws for it, whether the text is in NFC or NFD.
+func isHangulSyllable(rs []rune) bool {
+	if len(rs) < 2 {
+		return false
+	}
+	switch hangulSyllableType(rs[0]) {
+	case hangulL, hangulLV, hangulLVT:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package runewidth
+
+import (
+	"testing"
+)
+
+// decomposeHangul returns s with the precomposed Hangul syllables
+// decomposed into conjoining jamo, as in NFD.
+func decomposeHangul(s string) string {
+	var rs []rune
+	for _, r := range s {
+		if r < 0xAC00 || r > 0xD7A3 {
+			rs = append(rs, r)
+			continue
+		}
+		i := r - 0xAC00
+		rs = append(rs, 0x1100+i/(21*28), 0x1161+i%(21*28)/28)
+		if t := i % 28; t != 0 {
+			rs = append(rs, 0x11A7+t)
+		}
+	}
+	return string(rs)
+}
+
+func TestHangulSyllableType(t *testing.T) {
+	var tests = []struct {
+		in   rune
+		want int
+	}{
+		{'a', hangulNone},
+		{'\u1100', hangulL},
+		{'\u115f', hangulL},
+		{'\ua960', hangulL},
+		{'\u1160', hangulV},
+		{'\u11a7', hangulV},
+		{'\ud7b0', hangulV},
+		{'\u11a8', hangulT},
+		{'\ud7fb', hangulT},
+		{'가', hangulLV},
+		{'각', hangulLVT},
+		{'힣', hangulLVT},
+		{'\u3131', hangulNone}, // HANGUL LETTER KIYEOK, a compatibility jamo
+	}
+
+	for _, tt := range tests {
+		if got := hangulSyllableType(tt.in); got != tt.want {
+			t.Errorf("hangulSyllableType(%U) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestHangulNFCNFD(t *testing.T) {
+	var tests = []struct {
+		in   string
+		want int
+	}{
+		{"각", 2},
+		{"한국어", 6},
+		{"안녕하세요", 10},
+		{"대한민국 만세!", 14},
+		{"값어치", 6},
+	}
+
+	for _, tt := range tests {
+		nfd := decomposeHangul(tt.in)
+		if nfd == tt.in {
+			t.Fatalf("decomposeHangul(%q) did not decompose", tt.in)
+		}
+		for _, f := range []GraphemeWidthFunc{nil, UnicodeGraphemeWidth, FirstRuneWidth, MaxRuneWidth} {
+			c := &Condition{GraphemeWidth: f}
+			if got := c.StringWidth(tt.in); got != tt.want {
+				t.Errorf("StringWidth(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+			if got := c.StringWidth(nfd); got != tt.want {
+				t.Errorf("StringWidth(%q) = %d, want %d", nfd, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestHangulOldJamo(t *testing.T) {
+	var tests = []struct {
+		in   string
+		want int
+	}{
+		{"\u1112\u119e\u11ab", 2},       // HIEUH, ARAEA, NIEUN
+		{"\u1112\u119e\u11ab\uae00", 4}, // followed by a syllable
+		{"\ua964\u1161", 2},             // KIYEOK-RIEUL, A
+		{"\u1100\ud7b0", 2},             // KIYEOK, O-YEO
+		{"\u115f\u1161", 2},             // CHOSEONG FILLER, A
+		{"\uac00\u302e", 2},             // GA, HANGUL SINGLE DOT TONE MARK
+		{"\u1100\u1100\u1161\u11a8", 2}, // KIYEOK, KIYEOK, A, KIYEOK
+	}
+
+	for _, tt := range tests {
+		if got := StringWidth(tt.in); got != tt.want {
+			t.Errorf("StringWidth(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+		if got := Truncate(tt.in+tt.in, tt.want, ""); got != tt.in {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.in+tt.in, tt.want, got, tt.in)
+		}
+	}
+}
+
+func TestHangulLoneJamo(t *testing.T) {
+	var tests = []string{
+		"\u1161", // A
+		"\u11a8", // KIYEOK
+		"\ud7b0", // O-YEO
+		"\ud7cb", // NIEUN-RIEUL
+	}
+
+	for _, v := range tests {
+		want := StringWidth(v)
+		for _, s := range []string{v + "\u0301", v + "\u0301\u0323"} {
+			if got := StringWidth(s); got != want {
+				t.Errorf("StringWidth(%q) = %d, want %d, the width of %q", s, got, want, v)
+			}
+		}
+	}
+}
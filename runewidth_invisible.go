@@ -0,0 +1,95 @@
+package runewidth
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// EscapeStyle is the notation EscapeInvisible replaces invisible
+// characters with.
+type EscapeStyle int
+
+const (
+	// EscapeCodePoint writes the code point in angle brackets, as in
+	// "<U+202E>".
+	EscapeCodePoint EscapeStyle = iota
+	// EscapeGo writes a Go escape sequence, as in "\u202e".
+	EscapeGo
+)
+
+// escape returns the notation of r in style.
+func (style EscapeStyle) escape(r rune) string {
+	switch {
+	case style == EscapeGo && r > 0xFFFF:
+		return fmt.Sprintf(`\U%08x`, r)
+	case style == EscapeGo:
+		return fmt.Sprintf(`\u%04x`, r)
+	}
+	return fmt.Sprintf("<U+%04X>", r)
+}
+
+// isInvisible reports whether r is a format character which the table marks
+// as non printable, such as ZERO WIDTH SPACE or ZERO WIDTH NO-BREAK SPACE,
+// or a bidirectional control, which can reorder the text around it. ZERO
+// WIDTH JOINER and ZERO WIDTH NON-JOINER are not, as emoji sequences and
+// scripts such as Persian depend on them.
+func (c *Condition) isInvisible(r rune) bool {
+	switch {
+	case r == zwj || r == zwnj:
+		return false
+	case bidiControls.contains(r):
+		return true
+	}
+	return c.RuneClass(r) == ClassFormat
+}
+
+// StripInvisible removes the invisible format characters, which RuneWidth
+// measures as 0 and which can make text look like other text, from s. It
+// returns the result and its width.
+func (c *Condition) StripInvisible(s string) (string, int) {
+	return c.mapInvisible(s, func(rune) string {
+		return ""
+	})
+}
+
+// EscapeInvisible replaces the invisible format characters in s with their
+// code point in style, so that they can be seen. It returns the result and
+// its width.
+func (c *Condition) EscapeInvisible(s string, style EscapeStyle) (string, int) {
+	return c.mapInvisible(s, style.escape)
+}
+
+func (c *Condition) mapInvisible(s string, f func(rune) string) (string, int) {
+	i := strings.IndexFunc(s, c.isInvisible)
+	if i < 0 {
+		return s, c.StringWidth(s)
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	b.WriteString(s[:i])
+	for s = s[i:]; len(s) > 0; {
+		r, size := utf8.DecodeRuneInString(s)
+		if c.isInvisible(r) {
+			b.WriteString(f(r))
+		} else {
+			b.WriteString(s[:size])
+		}
+		s = s[size:]
+	}
+	out := b.String()
+	return out, c.StringWidth(out)
+}
+
+// StripInvisible removes the invisible format characters from s and returns
+// the result and its width with DefaultCondition.
+func StripInvisible(s string) (string, int) {
+	return DefaultCondition.StripInvisible(s)
+}
+
+// EscapeInvisible replaces the invisible format characters in s with their
+// code point in style and returns the result and its width with
+// DefaultCondition.
+func EscapeInvisible(s string, style EscapeStyle) (string, int) {
+	return DefaultCondition.EscapeInvisible(s, style)
+}
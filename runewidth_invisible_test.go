@@ -0,0 +1,49 @@
+package runewidth
+
+import (
+	"testing"
+)
+
+var invisibletests = []struct {
+	in       string
+	stripped string
+	width    int
+	angle    string
+	gostyle  string
+}{
+	{"", "", 0, "", ""},
+	{"abc", "abc", 3, "abc", "abc"},
+	{"ad\u200bmin", "admin", 5, "ad<U+200B>min", `ad\u200bmin`},
+	{"\ufeffreadme.txt", "readme.txt", 10, "<U+FEFF>readme.txt", `\ufeffreadme.txt`},
+	{"invoice\u202etxt.exe", "invoicetxt.exe", 14, "invoice<U+202E>txt.exe", `invoice\u202etxt.exe`},
+	{"\u2066\u2069", "", 0, "<U+2066><U+2069>", `\u2066\u2069`},
+	{"soft\u00adhyphen", "softhyphen", 10, "soft<U+00AD>hyphen", `soft\u00adhyphen`},
+	{"\u30bb\u200b\u30ab\u30a4", "\u30bb\u30ab\u30a4", 6, "\u30bb<U+200B>\u30ab\u30a4", `セ\u200bカイ`},
+	{"\u061cx", "x", 1, "<U+061C>x", `\u061cx`},
+	{"\U0001F3F4\U000E0067\U000E0062\U000E007F", "\U0001F3F4\U000E0067\U000E0062\U000E007F", 2, "\U0001F3F4\U000E0067\U000E0062\U000E007F", "\U0001F3F4\U000E0067\U000E0062\U000E007F"},
+	// joiners are kept
+	{"👩\u200d🍳", "👩\u200d🍳", 2, "👩\u200d🍳", "👩\u200d🍳"},
+	{"\u0645\u06cc\u200c\u062e\u0648\u0627\u0647\u0645", "\u0645\u06cc\u200c\u062e\u0648\u0627\u0647\u0645", 7, "\u0645\u06cc\u200c\u062e\u0648\u0627\u0647\u0645", "\u0645\u06cc\u200c\u062e\u0648\u0627\u0647\u0645"},
+	// combining marks are visible on their base
+	{"e\u0301", "e\u0301", 1, "e\u0301", "e\u0301"},
+}
+
+func TestStripInvisible(t *testing.T) {
+	for _, tt := range invisibletests {
+		out, w := StripInvisible(tt.in)
+		if out != tt.stripped || w != tt.width {
+			t.Errorf("StripInvisible(%q) = %q, %d, want %q, %d", tt.in, out, w, tt.stripped, tt.width)
+		}
+	}
+}
+
+func TestEscapeInvisible(t *testing.T) {
+	for _, tt := range invisibletests {
+		if out, w := EscapeInvisible(tt.in, EscapeCodePoint); out != tt.angle || w != StringWidth(tt.angle) {
+			t.Errorf("EscapeInvisible(%q, EscapeCodePoint) = %q, %d, want %q, %d", tt.in, out, w, tt.angle, StringWidth(tt.angle))
+		}
+		if out, w := EscapeInvisible(tt.in, EscapeGo); out != tt.gostyle || w != StringWidth(tt.gostyle) {
+			t.Errorf("EscapeInvisible(%q, EscapeGo) = %q, %d, want %q, %d", tt.in, out, w, tt.gostyle, StringWidth(tt.gostyle))
+		}
+	}
+}
@@ -7,3 +7,7 @@ func IsEastAsian() bool {
 	// TODO: Implement this for the web. Detect east asian in a compatible way, and return true.
 	return false
 }
+
+func isEastAsianEnv(getenv func(string) string) bool {
+	return false
+}
@@ -0,0 +1,220 @@
+package runewidth
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/rivo/uniseg"
+)
+
+// LastLine is how Justify aligns the last line of a paragraph.
+type LastLine int
+
+const (
+	// LastLineLeft leaves the last line as it is.
+	LastLineLeft LastLine = iota
+	// LastLineRight pads the last line on the left.
+	LastLineRight
+	// LastLineCenter pads the last line on the left with half of the
+	// missing cells, rounded down.
+	LastLineCenter
+	// LastLineJustify stretches the last line like the others.
+	LastLineJustify
+)
+
+// Justification configures Justify. The zero value stretches the spaces
+// between words only and leaves the last line of a paragraph as it is.
+type Justification struct {
+	LastLine LastLine
+
+	// StretchCJK distributes the missing cells between the characters of
+	// CJK text, which has no spaces to stretch, as well as between words.
+	StretchCJK bool
+}
+
+// noBreakBefore holds the characters which do not start a line in CJK
+// text, such as closing brackets, punctuation and small kana.
+const noBreakBefore = "、。，．・：；？！ー）」』】〕〉》］｝" +
+	"ぁぃぅぇぉっゃゅょゎァィゥェォッャュョヮヵヶ"
+
+// noBreakAfter holds the characters which do not end a line in CJK text.
+const noBreakAfter = "（「『【〔〈《［｛"
+
+// glue is what separates two boxes of a paragraph.
+type glue int
+
+const (
+	// glueBreak is a break inside a word wider than the line.
+	glueBreak glue = iota
+	// glueCJK is a break next to a wide character.
+	glueCJK
+	// glueSpace is a run of spaces, one cell wide unless stretched.
+	glueSpace
+)
+
+// box is a piece of a paragraph which is not broken across lines, with the
+// glue which separates it from the box before.
+type box struct {
+	glue  glue
+	start int
+	text  string
+	width int
+}
+
+// Justify wraps every line of s, taken as a paragraph, to w cells and
+// stretches the lines but the last to w cells. It breaks lines at white
+// space and next to wide characters, except before closing punctuation and
+// after opening brackets, and collapses runs of white space, such as tabs
+// and IDEOGRAPHIC SPACE, into one space. No-break spaces are part of the
+// words. A word wider
+// than w is broken between grapheme clusters, as Wrap does. Lines with
+// nothing to stretch are left as they are.
+func (c *Condition) Justify(s string, w int, j Justification) string {
+	paras := strings.Split(s, "\n")
+	for i, p := range paras {
+		paras[i] = c.justify(p, w, j)
+	}
+	return strings.Join(paras, "\n")
+}
+
+// justify justifies the paragraph p.
+func (c *Condition) justify(p string, w int, j Justification) string {
+	boxes := c.boxes(p, w)
+	var lines []string
+	for len(boxes) > 0 {
+		n, width := 1, boxes[0].width
+		for ; n < len(boxes); n++ {
+			bw := boxes[n].width
+			if boxes[n].glue == glueSpace {
+				bw++
+			}
+			if width+bw > w {
+				break
+			}
+			width += bw
+		}
+		lines = append(lines, j.line(boxes[:n], w-width, n == len(boxes)))
+		boxes = boxes[n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// boxes splits the paragraph p into the boxes lines are made of.
+func (c *Condition) boxes(p string, w int) []box {
+	var boxes []box
+	space := false
+	prev, prevWide := rune(0), false
+	g := uniseg.NewGraphemes(p)
+	for g.Next() {
+		from, to := g.Positions()
+		cluster := p[from:to]
+		rs := g.Runes()
+		if isBreakingSpace(rs) {
+			space = true
+			continue
+		}
+		cw := c.stringClusterWidth(cluster, rs)
+		wide := cw == 2
+		switch {
+		case len(boxes) == 0:
+			boxes = append(boxes, box{glue: glueBreak, start: from})
+		case space:
+			boxes = append(boxes, box{glue: glueSpace, start: from})
+		case (wide || prevWide) &&
+			!strings.ContainsRune(noBreakBefore, rs[0]) &&
+			!strings.ContainsRune(noBreakAfter, prev):
+			boxes = append(boxes, box{glue: glueCJK, start: from})
+		}
+		b := &boxes[len(boxes)-1]
+		b.text = p[b.start:to]
+		b.width += cw
+		space, prev, prevWide = false, rs[0], wide
+	}
+
+	var out []box
+	for _, b := range boxes {
+		if b.width <= w {
+			out = append(out, b)
+			continue
+		}
+		for i, piece := range strings.Split(c.Wrap(b.text, w), "\n") {
+			gl := glueBreak
+			if i == 0 {
+				gl = b.glue
+			}
+			out = append(out, box{glue: gl, text: piece, width: c.StringWidth(piece)})
+		}
+	}
+	return out
+}
+
+// isBreakingSpace reports whether the grapheme cluster rs is white space
+// lines break at, such as a space, a tab or an IDEOGRAPHIC SPACE. The
+// no-break spaces are not.
+func isBreakingSpace(rs []rune) bool {
+	if len(rs) != 1 {
+		return false
+	}
+	switch rs[0] {
+	case '\u00a0', '\u2007', '\u202f':
+		return false
+	}
+	return unicode.IsSpace(rs[0])
+}
+
+// stretches reports whether the glue takes the missing cells of a line.
+func (j Justification) stretches(gl glue) bool {
+	return gl == glueSpace || (gl == glueCJK && j.StretchCJK)
+}
+
+// line joins the boxes of a line, which is extra cells narrower than the
+// width, and aligns it.
+func (j Justification) line(boxes []box, extra int, last bool) string {
+	var b strings.Builder
+	if last {
+		switch j.LastLine {
+		case LastLineLeft:
+			extra = 0
+		case LastLineRight:
+			writeSpaces(&b, extra)
+			extra = 0
+		case LastLineCenter:
+			writeSpaces(&b, extra/2)
+			extra = 0
+		}
+	}
+	if extra < 0 {
+		extra = 0
+	}
+
+	n := 0
+	for _, bx := range boxes[1:] {
+		if j.stretches(bx.glue) {
+			n++
+		}
+	}
+	k := 0
+	for i, bx := range boxes {
+		if i > 0 && bx.glue == glueSpace {
+			b.WriteByte(' ')
+		}
+		if i > 0 && j.stretches(bx.glue) {
+			// Give the gaps on the left one more cell if the missing cells
+			// do not divide evenly.
+			m := extra / n
+			if k < extra%n {
+				m++
+			}
+			writeSpaces(&b, m)
+			k++
+		}
+		b.WriteString(bx.text)
+	}
+	return b.String()
+}
+
+// Justify wraps and stretches the paragraphs of s to w cells with
+// DefaultCondition.
+func Justify(s string, w int, j Justification) string {
+	return DefaultCondition.Justify(s, w, j)
+}
@@ -0,0 +1,76 @@
+package runewidth
+
+import (
+	"strings"
+	"testing"
+)
+
+var justifytests = []struct {
+	in   string
+	w    int
+	j    Justification
+	want string
+}{
+	{"", 10, Justification{}, ""},
+	{"  a   b  ", 10, Justification{}, "a b"},
+	{"the quick brown fox jumps over the lazy dog", 16, Justification{},
+		"the  quick brown\nfox  jumps  over\nthe lazy dog"},
+	{"the quick brown fox jumps over the lazy dog", 16, Justification{LastLine: LastLineRight},
+		"the  quick brown\nfox  jumps  over\n    the lazy dog"},
+	{"the quick brown fox jumps over the lazy dog", 16, Justification{LastLine: LastLineCenter},
+		"the  quick brown\nfox  jumps  over\n  the lazy dog"},
+	{"the quick brown fox jumps over the lazy dog", 16, Justification{LastLine: LastLineJustify},
+		"the  quick brown\nfox  jumps  over\nthe   lazy   dog"},
+	{"日本語の文章は単語の間に空白がない。", 11, Justification{},
+		"日本語の文\n章は単語の\n間に空白が\nない。"},
+	{"日本語の文章は単語の間に空白がない。", 11, Justification{StretchCJK: true},
+		"日 本語の文\n章 は単語の\n間 に空白が\nない。"},
+	// 、 does not start a line
+	{"あいうえお、かきく", 10, Justification{StretchCJK: true},
+		"あ い うえ\nお、かきく"},
+	// 「 does not end a line
+	{"あいう「えお」", 8, Justification{},
+		"あいう\n「えお」"},
+	{"Go言語 is fun", 12, Justification{},
+		"Go言語    is\nfun"},
+	{"Go言語 is fun", 12, Justification{StretchCJK: true},
+		"Go 言 語  is\nfun"},
+	{"a supercalifragilistic b", 8, Justification{},
+		"a\nsupercal\nifragili\nstic b"},
+	{"aa bb cc\n\ndd ee", 6, Justification{},
+		"aa  bb\ncc\n\ndd ee"},
+	{"a\tb \t c", 10, Justification{},
+		"a b c"},
+	{"the\tquick brown", 10, Justification{},
+		"the  quick\nbrown"},
+	{"\u3000日本\u3000語", 10, Justification{},
+		"日本 語"},
+	{"日本\u3000語の文章", 8, Justification{},
+		"日本  語\nの文章"},
+	{"a\u00a0b c", 4, Justification{},
+		"a\u00a0b\nc"},
+}
+
+func TestJustify(t *testing.T) {
+	for _, tt := range justifytests {
+		if got := Justify(tt.in, tt.w, tt.j); got != tt.want {
+			t.Errorf("Justify(%q, %d, %+v) = %q, want %q", tt.in, tt.w, tt.j, got, tt.want)
+		}
+	}
+}
+
+func TestJustifyWidth(t *testing.T) {
+	in := "吾輩は猫である。名前はまだ無い。 The quick brown fox jumps over the lazy dog."
+	for w := 12; w <= 30; w++ {
+		out := Justify(in, w, Justification{LastLine: LastLineJustify, StretchCJK: true})
+		for _, line := range strings.Split(out, "\n") {
+			if !strings.Contains(line, " ") && StringWidth(line) == len(line) {
+				// a single Latin word cannot be stretched
+				continue
+			}
+			if got := StringWidth(line); got != w {
+				t.Errorf("Justify(%q, %d) has line %q of width %d", in, w, line, got)
+			}
+		}
+	}
+}
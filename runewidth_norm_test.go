@@ -0,0 +1,66 @@
+package runewidth
+
+import (
+	"testing"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var normalizationtests = []string{
+	// Latin
+	"Ångström", "café", "naïve", "façonnée", "Ǖ", "ḱ",
+	// Greek, whose capitals the table makes wide
+	"Άλφα", "ΐ", "Ώ",
+	// Vietnamese
+	"Tiếng Việt", "Người", "ặ ẫ ỡ ự",
+	// Korean
+	"한국어", "안녕하세요",
+	// Japanese dakuten and handakuten
+	"が", "がぎぐげご", "ぱぴぷぺぽ", "ガギグゲゴヴ", "パピプペポ", "ゔ",
+	// symbols with canonical decompositions
+	"≠", "≮", "∉", "Å",
+}
+
+func TestNormalize(t *testing.T) {
+	conditions := []*Condition{
+		{Normalize: true},
+		{Normalize: true, AmbiguousWidth: 1},
+		{Normalize: true, AmbiguousWidth: 2},
+		{Normalize: true, GraphemeWidth: SumRuneWidth},
+		{Normalize: true, GraphemeWidth: FirstRuneWidth},
+	}
+
+	for _, s := range normalizationtests {
+		nfc, nfd := norm.NFC.String(s), norm.NFD.String(s)
+		if nfc == nfd {
+			t.Fatalf("%q has no decomposition", s)
+		}
+		for _, c := range conditions {
+			want := c.StringWidth(nfc)
+			if got := c.StringWidth(nfd); got != want {
+				t.Errorf("%+v: StringWidth(%q) = %d, want StringWidth(%q) = %d", c, nfd, got, nfc, want)
+			}
+			if got, trunc := c.Truncate(nfd, want-1, ""), norm.NFD.String(c.Truncate(nfc, want-1, "")); got != trunc {
+				t.Errorf("%+v: Truncate(%q, %d) = %q, want %q", c, nfd, want-1, got, trunc)
+			}
+		}
+	}
+}
+
+func TestNormalizeDakuten(t *testing.T) {
+	c := &Condition{Normalize: true}
+	for _, s := range []string{"が", "が", "ヴ", "ヴ"} {
+		if got := c.StringWidth(s); got != 2 {
+			t.Errorf("StringWidth(%q) = %d, want %d", s, got, 2)
+		}
+	}
+}
+
+func TestNormalizeSingleton(t *testing.T) {
+	c := &Condition{Normalize: true, AmbiguousWidth: 2}
+	for _, s := range []string{"\u2126", "\u212B", "\u1FFD"} { // OHM SIGN, ANGSTROM SIGN, GREEK OXIA
+		if got, want := c.StringWidth(s), c.StringWidth(norm.NFC.String(s)); got != want {
+			t.Errorf("StringWidth(%q) = %d, want %d", s, got, want)
+		}
+	}
+}
@@ -62,12 +62,18 @@ func isEastAsian(locale string) bool {
 
 // IsEastAsian return true if the current locale is CJK
 func IsEastAsian() bool {
-	locale := os.Getenv("LC_ALL")
+	return isEastAsianEnv(os.Getenv)
+}
+
+// isEastAsianEnv reports whether the locale named by LC_ALL, LC_CTYPE or
+// LANG, looked up with getenv, is CJK.
+func isEastAsianEnv(getenv func(string) string) bool {
+	locale := getenv("LC_ALL")
 	if locale == "" {
-		locale = os.Getenv("LC_CTYPE")
+		locale = getenv("LC_CTYPE")
 	}
 	if locale == "" {
-		locale = os.Getenv("LANG")
+		locale = getenv("LANG")
 	}
 
 	// ignore C locale
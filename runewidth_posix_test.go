@@ -96,3 +96,29 @@ func TestIsEastAsianLANG(t *testing.T) {
 		}
 	}
 }
+
+func TestNewConditionFromEnvLocale(t *testing.T) {
+	testcases := []struct {
+		env  map[string]string
+		want int
+	}{
+		{map[string]string{"LANG": "ja_JP.UTF-8"}, 2},
+		{map[string]string{"LANG": "en_US.UTF-8"}, 0},
+		{map[string]string{"LC_ALL": "C", "LANG": "ja_JP.UTF-8"}, 0},
+		{map[string]string{"LC_CTYPE": "zh_CN.GB2312", "LANG": "en_US.UTF-8"}, 2},
+		{map[string]string{"TERM": "xterm-256color", "VTE_VERSION": "6003", "LANG": "ko_KR.UTF-8"}, 2},
+		{map[string]string{"TERM": "xterm-256color", "VTE_VERSION": "6003", "LANG": "en_US.UTF-8"}, 1},
+		{map[string]string{"TERM": "xterm-256color", "LANG": "ja_JP.UTF-8"}, 1},
+		{map[string]string{"TERM": "xterm-kitty", "KITTY_WINDOW_ID": "1", "LANG": "ja_JP.UTF-8"}, 1},
+	}
+
+	for _, tt := range testcases {
+		if got := NewConditionFromEnv(tt.env).AmbiguousWidth; got != tt.want {
+			t.Errorf("NewConditionFromEnv(%v).AmbiguousWidth = %d, want %d", tt.env, got, tt.want)
+		}
+	}
+	c := NewConditionFromEnv(map[string]string{"LANG": "ja_JP.UTF-8"})
+	if got := c.StringWidth("\u2606"); got != 2 {
+		t.Errorf("StringWidth(%q) in a Japanese locale = %d, want 2", "\u2606", got)
+	}
+}
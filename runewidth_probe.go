@@ -0,0 +1,174 @@
+package runewidth
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// ErrProbeTimeout is returned by Probe when the terminal does not report the
+// cursor position in time.
+var ErrProbeTimeout = errors.New("runewidth: timeout waiting for cursor position report")
+
+// probeVersions holds a wide emoji for each Unicode version the prober can
+// tell apart, oldest first.
+var probeVersions = []struct {
+	version UnicodeVersion
+	sample  string
+}{
+	{UnicodeVersion{9, 0}, "\U0001F923"},  // ROLLING ON THE FLOOR LAUGHING
+	{UnicodeVersion{10, 0}, "\U0001F929"}, // GRINNING FACE WITH STAR EYES
+	{UnicodeVersion{11, 0}, "\U0001F970"}, // SMILING FACE WITH SMILING EYES AND THREE HEARTS
+	{UnicodeVersion{12, 0}, "\U0001F971"}, // YAWNING FACE
+	{UnicodeVersion{13, 0}, "\U0001F972"}, // SMILING FACE WITH TEAR
+}
+
+const (
+	probeAmbiguous = "\u2606"                     // WHITE STAR
+	probeEmoji     = "\u263A\uFE0F"               // WHITE SMILING FACE, VS16
+	probeZWJ       = "\U0001F469\u200D\U0001F373" // WOMAN, ZWJ, COOKING
+)
+
+var reDSR = regexp.MustCompile(`\x1b\[(\d+);(\d+)R`)
+
+// Probe asks the terminal behind rw how wide it renders ambiguous
+// characters, emoji with VS16, ZWJ sequences and emoji of recent Unicode
+// versions, and returns a Condition calibrated to the answers.
+//
+// Probe prints each sample at the start of the current line, followed by a
+// Device Status Report request (ESC [ 6 n), and reads the column from the
+// cursor position report. The line is cleared afterwards. rw must be a
+// terminal in raw mode, so that the reports are neither echoed nor line
+// buffered. Each report must arrive within timeout, or Probe fails with
+// ErrProbeTimeout. If rw has a SetReadDeadline method, as *os.File does,
+// it is used to stop reading; otherwise the Read pending at a timeout keeps
+// running in a goroutine until it returns, and its data is lost.
+func Probe(rw io.ReadWriter, timeout time.Duration) (*Condition, error) {
+	p := &prober{rw: rw, timeout: timeout}
+	defer p.clear()
+
+	c := NewCondition()
+	w, err := p.width(probeAmbiguous)
+	if err != nil {
+		return nil, err
+	}
+	c.AmbiguousWidth = w
+
+	if w, err = p.width(probeEmoji); err != nil {
+		return nil, err
+	}
+	c.EmojiPresentation = w == 2
+
+	if w, err = p.width(probeZWJ); err != nil {
+		return nil, err
+	}
+	c.SplitZWJ = w > 2
+
+	c.UnicodeVersion = UnicodeVersion{8, 0}
+	for _, v := range probeVersions {
+		if w, err = p.width(v.sample); err != nil {
+			return nil, err
+		}
+		if w != 2 {
+			break
+		}
+		c.UnicodeVersion = v.version
+	}
+	return c, nil
+}
+
+type prober struct {
+	rw      io.ReadWriter
+	timeout time.Duration
+	buf     []byte
+	pending chan readResult // Read running in a goroutine, if any
+}
+
+type readResult struct {
+	b   []byte
+	err error
+}
+
+// width prints s at the start of the line and returns the number of cells
+// the cursor moved.
+func (p *prober) width(s string) (int, error) {
+	if _, err := io.WriteString(p.rw, "\r"+s+"\x1b[6n"); err != nil {
+		return 0, err
+	}
+	col, err := p.readColumn()
+	if err != nil {
+		return 0, err
+	}
+	return col - 1, nil
+}
+
+func (p *prober) clear() {
+	io.WriteString(p.rw, "\r\x1b[2K")
+}
+
+// readColumn reads a cursor position report and returns its column.
+func (p *prober) readColumn() (int, error) {
+	deadline := time.Now().Add(p.timeout)
+	for {
+		if m := reDSR.FindSubmatchIndex(p.buf); m != nil {
+			col, err := strconv.Atoi(string(p.buf[m[4]:m[5]]))
+			p.buf = p.buf[m[1]:]
+			if err != nil {
+				return 0, fmt.Errorf("runewidth: invalid cursor position report: %v", err)
+			}
+			return col, nil
+		}
+		if err := p.read(deadline); err != nil {
+			return 0, err
+		}
+	}
+}
+
+type readDeadliner interface {
+	SetReadDeadline(t time.Time) error
+}
+
+func (p *prober) read(deadline time.Time) error {
+	if d, ok := p.rw.(readDeadliner); ok && d.SetReadDeadline(deadline) == nil {
+		defer d.SetReadDeadline(time.Time{})
+		b := make([]byte, 64)
+		n, err := p.rw.Read(b)
+		p.buf = append(p.buf, b[:n]...)
+		if err != nil {
+			if n > 0 {
+				return nil
+			}
+			if time.Now().After(deadline) {
+				return ErrProbeTimeout
+			}
+			return err
+		}
+		return nil
+	}
+
+	if p.pending == nil {
+		ch := make(chan readResult, 1)
+		go func() {
+			b := make([]byte, 64)
+			n, err := p.rw.Read(b)
+			ch <- readResult{b[:n], err}
+		}()
+		p.pending = ch
+	}
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	select {
+	case r := <-p.pending:
+		p.pending = nil
+		p.buf = append(p.buf, r.b...)
+		if len(r.b) == 0 {
+			return r.err
+		}
+		return nil
+	case <-timer.C:
+		return ErrProbeTimeout
+	}
+}
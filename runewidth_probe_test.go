@@ -0,0 +1,86 @@
+package runewidth
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeTerminal renders the text written to it with a Condition and answers
+// cursor position requests.
+type fakeTerminal struct {
+	c       *Condition
+	line    string
+	replies bytes.Buffer
+	silent  chan struct{} // blocks reads when not nil
+}
+
+func (t *fakeTerminal) Write(b []byte) (int, error) {
+	s := string(b)
+	for len(s) > 0 {
+		switch {
+		case s[0] == '\r':
+			t.line = ""
+			s = s[1:]
+		case strings.HasPrefix(s, "\x1b[6n"):
+			fmt.Fprintf(&t.replies, "\x1b[24;%dR", t.c.StringWidth(t.line)+1)
+			s = s[4:]
+		case strings.HasPrefix(s, "\x1b[2K"):
+			s = s[4:]
+		default:
+			n := strings.IndexAny(s, "\r\x1b")
+			if n < 0 {
+				n = len(s)
+			}
+			t.line += s[:n]
+			s = s[n:]
+		}
+	}
+	return len(b), nil
+}
+
+func (t *fakeTerminal) Read(b []byte) (int, error) {
+	if t.silent != nil {
+		<-t.silent
+	}
+	// Hand out the replies in small pieces, like a slow tty.
+	if len(b) > 5 {
+		b = b[:5]
+	}
+	return t.replies.Read(b)
+}
+
+func TestProbe(t *testing.T) {
+	for _, name := range Terminals() {
+		want := NewConditionForTerminal(name)
+		got, err := Probe(&fakeTerminal{c: want}, time.Second)
+		if err != nil {
+			t.Errorf("Probe(%s) failed: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Probe(%s) = %+v, want %+v", name, got, want)
+		}
+	}
+
+	want := &Condition{AmbiguousWidth: 2, UnicodeVersion: UnicodeVersion{8, 0}}
+	got, err := Probe(&fakeTerminal{c: want}, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Probe() = %+v, want %+v", got, want)
+	}
+}
+
+func TestProbeTimeout(t *testing.T) {
+	term := &fakeTerminal{c: NewCondition(), silent: make(chan struct{})}
+	defer close(term.silent)
+
+	if _, err := Probe(term, 10*time.Millisecond); err != ErrProbeTimeout {
+		t.Errorf("Probe() error = %v, want %v", err, ErrProbeTimeout)
+	}
+}
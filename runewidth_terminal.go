@@ -0,0 +1,144 @@
+package runewidth
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// terminals holds the conditions matching how terminal emulators render
+// characters in their default configuration.
+var terminals = map[string]Condition{
+	// xterm uses its own wcwidth with narrow ambiguous characters (unless
+	// started with -cjk_width) and draws the emoji of a sequence one by one.
+	"xterm": {
+		AmbiguousWidth: 1,
+		UnicodeVersion: UnicodeVersion{13, 0},
+		SplitZWJ:       true,
+	},
+	// VTE (GNOME Terminal, Tilix, ...) follows GLib's tables.
+	"vte": {
+		AmbiguousWidth: 1,
+		UnicodeVersion: UnicodeVersion{13, 0},
+		SplitZWJ:       true,
+	},
+	// kitty widens text-style emoji with VS16 and renders ZWJ sequences as
+	// one glyph.
+	"kitty": {
+		AmbiguousWidth:    1,
+		UnicodeVersion:    UnicodeVersion{13, 0},
+		EmojiPresentation: true,
+	},
+	// WezTerm defaults to the widths of Unicode 9 (unicode_version = 9).
+	"wezterm": {
+		AmbiguousWidth:    1,
+		UnicodeVersion:    UnicodeVersion{9, 0},
+		EmojiPresentation: true,
+	},
+	"iterm2": {
+		AmbiguousWidth:    1,
+		UnicodeVersion:    UnicodeVersion{9, 0},
+		EmojiPresentation: true,
+	},
+	"windows-terminal": {
+		AmbiguousWidth:    1,
+		UnicodeVersion:    UnicodeVersion{13, 0},
+		EmojiPresentation: true,
+	},
+	// tmux measures with the wcwidth of the C library and ignores VS16.
+	"tmux": {
+		AmbiguousWidth: 1,
+		UnicodeVersion: UnicodeVersion{11, 0},
+		SplitZWJ:       true,
+	},
+}
+
+// Terminals returns the names accepted by NewConditionForTerminal in
+// sorted order.
+func Terminals() []string {
+	names := make([]string, 0, len(terminals))
+	for name := range terminals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewConditionForTerminal returns a new Condition which measures like the
+// named terminal emulator, such as "kitty" or "xterm". The name is case
+// insensitive. An unknown name returns the same Condition as NewCondition.
+func NewConditionForTerminal(name string) *Condition {
+	c := terminals[strings.ToLower(name)]
+	return &c
+}
+
+// DetectTerminal returns the name of the terminal emulator described by the
+// environment variables in env, such as TERM, TERM_PROGRAM, VTE_VERSION,
+// WT_SESSION, KITTY_WINDOW_ID and TMUX, or "" if it is not known. A nil env
+// reads the environment of the process.
+func DetectTerminal(env map[string]string) string {
+	if env == nil {
+		env = environ()
+	}
+	term := env["TERM"]
+	program := env["TERM_PROGRAM"]
+
+	switch {
+	// tmux redraws the screen itself, so it decides over the outer terminal.
+	case env["TMUX"] != "" || program == "tmux" || strings.HasPrefix(term, "tmux"):
+		return "tmux"
+	case env["KITTY_WINDOW_ID"] != "" || term == "xterm-kitty":
+		return "kitty"
+	case program == "WezTerm" || term == "wezterm":
+		return "wezterm"
+	case program == "iTerm.app":
+		return "iterm2"
+	case env["WT_SESSION"] != "":
+		return "windows-terminal"
+	case env["VTE_VERSION"] != "":
+		return "vte"
+	case strings.HasPrefix(term, "xterm"):
+		return "xterm"
+	}
+	return ""
+}
+
+// localeAmbiguous holds the terminals whose width of the East Asian
+// Ambiguous characters follows the locale. VTE guesses it from the locale;
+// the others draw them narrow unless configured otherwise. An unknown
+// terminal is assumed to follow the locale.
+var localeAmbiguous = map[string]bool{
+	"":    true,
+	"vte": true,
+}
+
+// NewConditionFromEnv returns a new Condition for the terminal emulator
+// detected by DetectTerminal from env. If the terminal follows the locale
+// and the locale named by LC_ALL, LC_CTYPE or LANG in env is CJK, East Asian
+// Ambiguous characters are two cells wide. A nil env reads the environment
+// and the locale of the process, which is the console code page on Windows.
+func NewConditionFromEnv(env map[string]string) *Condition {
+	var eastAsian bool
+	if env == nil {
+		env = environ()
+		eastAsian = IsEastAsian()
+	} else {
+		eastAsian = isEastAsianEnv(func(key string) string { return env[key] })
+	}
+	name := DetectTerminal(env)
+	c := NewConditionForTerminal(name)
+	if eastAsian && localeAmbiguous[name] {
+		c.AmbiguousWidth = 2
+	}
+	return c
+}
+
+func environ() map[string]string {
+	env := map[string]string{}
+	for _, kv := range os.Environ() {
+		if i := strings.IndexByte(kv, '='); i > 0 {
+			env[kv[:i]] = kv[i+1:]
+		}
+	}
+	return env
+}
@@ -0,0 +1,118 @@
+package runewidth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewConditionForTerminal(t *testing.T) {
+	var tests = []struct {
+		terminal string
+		widths   map[string]int
+	}{
+		{"", map[string]int{
+			"☆": 2, "α": 2, "☺️": 1, "☝🏻": 1, "👩‍🍳": 2, "🥲": 2, "🤩": 2, "あ": 2, "ü": 1,
+		}},
+		{"xterm", map[string]int{
+			"☆": 1, "α": 1, "☺️": 1, "☝🏻": 1, "👩‍🍳": 4, "🥲": 2, "🤩": 2, "あ": 2, "ü": 1,
+		}},
+		{"vte", map[string]int{
+			"☆": 1, "α": 1, "☺️": 1, "☝🏻": 1, "👩‍🍳": 4, "🥲": 2, "🤩": 2, "あ": 2, "ü": 1,
+		}},
+		{"kitty", map[string]int{
+			"☆": 1, "α": 1, "☺️": 2, "☝🏻": 2, "👩‍🍳": 2, "🥲": 2, "🤩": 2, "あ": 2, "ü": 1,
+		}},
+		{"WezTerm", map[string]int{
+			"☆": 1, "α": 1, "☺️": 2, "☝🏻": 2, "👩‍🍳": 2, "🥲": 1, "🤩": 1, "あ": 2, "ü": 1,
+		}},
+		{"iterm2", map[string]int{
+			"☆": 1, "α": 1, "☺️": 2, "☝🏻": 2, "👩‍🍳": 2, "🥲": 1, "🤩": 1, "あ": 2, "ü": 1,
+		}},
+		{"windows-terminal", map[string]int{
+			"☆": 1, "α": 1, "☺️": 2, "☝🏻": 2, "👩‍🍳": 2, "🥲": 2, "🤩": 2, "あ": 2, "ü": 1,
+		}},
+		{"tmux", map[string]int{
+			"☆": 1, "α": 1, "☺️": 1, "☝🏻": 1, "👩‍🍳": 4, "🥲": 1, "🤩": 2, "あ": 2, "ü": 1,
+		}},
+	}
+
+	for _, tt := range tests {
+		c := NewConditionForTerminal(tt.terminal)
+		for s, want := range tt.widths {
+			if got := c.StringWidth(s); got != want {
+				t.Errorf("NewConditionForTerminal(%q).StringWidth(%q) = %d, want %d", tt.terminal, s, got, want)
+			}
+		}
+	}
+}
+
+func TestTerminals(t *testing.T) {
+	names := Terminals()
+	if len(names) != len(terminals) {
+		t.Fatalf("Terminals() = %q, want %d names", names, len(terminals))
+	}
+	for _, name := range names {
+		if got, want := *NewConditionForTerminal(name), terminals[name]; !reflect.DeepEqual(got, want) {
+			t.Errorf("NewConditionForTerminal(%q) = %+v, want %+v", name, got, want)
+		}
+	}
+	if got := NewConditionForTerminal("unknown"); !reflect.DeepEqual(got, NewCondition()) {
+		t.Errorf("NewConditionForTerminal(%q) = %+v, want %+v", "unknown", got, NewCondition())
+	}
+}
+
+func TestParseUnicodeVersion(t *testing.T) {
+	var tests = []struct {
+		in   string
+		want UnicodeVersion
+		ok   bool
+	}{
+		{"9.0", UnicodeVersion{9, 0}, true},
+		{"12.1.0", UnicodeVersion{12, 1}, true},
+		{"13", UnicodeVersion{13, 0}, true},
+		{"", UnicodeVersion{}, false},
+		{"x.1", UnicodeVersion{}, false},
+		{"0.1", UnicodeVersion{}, false},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseUnicodeVersion(tt.in)
+		if got != tt.want || (err == nil) != tt.ok {
+			t.Errorf("ParseUnicodeVersion(%q) = %v, %v, want %v, ok %v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDetectTerminal(t *testing.T) {
+	var tests = []struct {
+		env  map[string]string
+		want string
+	}{
+		{map[string]string{}, ""},
+		{map[string]string{"TERM": "dumb"}, ""},
+		{map[string]string{"TERM": "xterm-256color"}, "xterm"},
+		{map[string]string{"TERM": "xterm-256color", "VTE_VERSION": "6003"}, "vte"},
+		{map[string]string{"TERM": "xterm-kitty", "KITTY_WINDOW_ID": "1"}, "kitty"},
+		{map[string]string{"TERM": "xterm-256color", "KITTY_WINDOW_ID": "1"}, "kitty"},
+		{map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "WezTerm"}, "wezterm"},
+		{map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "iTerm.app"}, "iterm2"},
+		{map[string]string{"WT_SESSION": "0b6c3a9e-7a1c-4c4e-9d9a-3f1e2b6c7d8e"}, "windows-terminal"},
+		{map[string]string{"TERM": "screen-256color", "TMUX": "/tmp/tmux-1000/default,1234,0", "KITTY_WINDOW_ID": "1"}, "tmux"},
+		{map[string]string{"TERM": "tmux-256color"}, "tmux"},
+	}
+
+	for _, tt := range tests {
+		if got := DetectTerminal(tt.env); got != tt.want {
+			t.Errorf("DetectTerminal(%v) = %q, want %q", tt.env, got, tt.want)
+		}
+		if got, want := NewConditionFromEnv(tt.env), NewConditionForTerminal(tt.want); !reflect.DeepEqual(got, want) {
+			t.Errorf("NewConditionFromEnv(%v) = %+v, want %+v", tt.env, got, want)
+		}
+	}
+}
+
+func TestDetectTerminalEnviron(t *testing.T) {
+	if got, want := DetectTerminal(nil), DetectTerminal(environ()); got != want {
+		t.Errorf("DetectTerminal(nil) = %q, want %q", got, want)
+	}
+}
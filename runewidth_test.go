@@ -49,6 +49,14 @@ func isCompact(t *testing.T, ti *tableInfo) bool {
 	return true
 }
 
+func TestTrieWidth(t *testing.T) {
+	for r := rune(0); r <= utf8.MaxRune; r++ {
+		if got, want := trieWidth(r), wcwidth9_width(r); got != want {
+			t.Fatalf("trieWidth(%U) = %d, want %d", r, got, want)
+		}
+	}
+}
+
 var runewidthtests = []struct {
 	in  rune
 	out int
@@ -184,6 +192,24 @@ END`
 	}
 }
 
+func TestWrapGraphemes(t *testing.T) {
+	var tests = []struct {
+		in  string
+		w   int
+		out string
+	}{
+		{"\U0001F469\u200d\U0001F373\U0001F469\u200d\U0001F373", 3, "\U0001F469\u200d\U0001F373\n\U0001F469\u200d\U0001F373"},
+		{"e\u0301e\u0301e\u0301", 2, "e\u0301e\u0301\ne\u0301"},
+		{"\u3042\u3044", 1, "\u3042\n\u3044"},
+		{"ab\r\ncd", 1, "a\nb\r\nc\nd"},
+	}
+	for _, tt := range tests {
+		if out := Wrap(tt.in, tt.w); out != tt.out {
+			t.Errorf("Wrap(%q, %d) = %q, want %q", tt.in, tt.w, out, tt.out)
+		}
+	}
+}
+
 func TestTruncateNoNeeded(t *testing.T) {
 	s := "あいうえおあい"
 	expected := "あいうえおあい"
@@ -260,3 +286,51 @@ func TestZeroWidthJoiner(t *testing.T) {
 		}
 	}
 }
+
+func TestStringWidthLatin(t *testing.T) {
+	c := NewCondition()
+
+	var tests = []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"a\r\nb", 2},
+		{"a\tb", 2},
+		{"soft\u00adhyphen", 10},
+		{"Smörgåsbord", 11},
+		{"˄ˇ", 4},
+		{"e\u0301", 1},
+		{"abc\xff", 5},
+	}
+
+	for _, tt := range tests {
+		if got := c.StringWidth(tt.in); got != tt.want {
+			t.Errorf("StringWidth(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAmbiguousWidth(t *testing.T) {
+	c := &Condition{AmbiguousWidth: 2}
+
+	var tests = []struct {
+		in   string
+		want int
+	}{
+		{"a", 1},
+		{"¡", 2},
+		{"über", 5},
+		{"│", 2},
+		{"\u00e9", 2},
+		{"e\u0301", 1},
+		{"あ", 2},
+	}
+
+	for _, tt := range tests {
+		if got := c.StringWidth(tt.in); got != tt.want {
+			t.Errorf("StringWidth(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
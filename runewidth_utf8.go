@@ -0,0 +1,160 @@
+package runewidth
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// InvalidUTF8 is how the string functions measure the bytes of a string
+// which are not valid UTF-8. Each such byte is measured on its own, even if
+// it starts a truncated sequence, and Truncate and Wrap keep it as is.
+type InvalidUTF8 int
+
+const (
+	// InvalidAsRuneError measures each invalid byte as utf8.RuneError
+	// (U+FFFD), which the table makes two cells wide.
+	InvalidAsRuneError InvalidUTF8 = iota
+	// InvalidAsReplacement measures each invalid byte as one cell, as
+	// terminals which draw a narrow replacement character for it do.
+	InvalidAsReplacement
+	// InvalidAsZero measures invalid bytes as zero width.
+	InvalidAsZero
+)
+
+// width returns the number of cells in an invalid byte.
+func (p InvalidUTF8) width() int {
+	switch p {
+	case InvalidAsReplacement:
+		return 1
+	case InvalidAsZero:
+		return 0
+	}
+	return runeWidth(utf8.RuneError)
+}
+
+// InvalidUTF8Error is returned by StringWidthE, TruncateE, WrapE, FillLeftE
+// and FillRightE for a string which is not valid UTF-8.
+type InvalidUTF8Error struct {
+	Offset int // byte offset of the first invalid byte
+}
+
+func (e *InvalidUTF8Error) Error() string {
+	return fmt.Sprintf("runewidth: invalid UTF-8 at offset %d", e.Offset)
+}
+
+// validUTF8 returns an *InvalidUTF8Error if s is not valid UTF-8.
+func validUTF8(s string) error {
+	if utf8.ValidString(s) {
+		return nil
+	}
+	for i, r := range s {
+		if r == utf8.RuneError {
+			if _, size := utf8.DecodeRuneInString(s[i:]); size == 1 {
+				return &InvalidUTF8Error{Offset: i}
+			}
+		}
+	}
+	panic("unreachable")
+}
+
+// stringClusterWidth returns the width of the grapheme cluster str, which
+// decodes to rs. The bytes of str which are not valid UTF-8 are measured as
+// c.InvalidUTF8 says, and the rest as a cluster of its own.
+func (c *Condition) stringClusterWidth(str string, rs []rune) int {
+	if c.InvalidUTF8 == InvalidAsRuneError || utf8.ValidString(str) {
+		return c.clusterWidth(rs)
+	}
+	width := 0
+	valid := rs[:0:0]
+	for len(str) > 0 {
+		r, size := utf8.DecodeRuneInString(str)
+		if r == utf8.RuneError && size == 1 {
+			width += c.InvalidUTF8.width()
+		} else {
+			valid = append(valid, r)
+		}
+		str = str[size:]
+	}
+	if len(valid) > 0 {
+		width += c.clusterWidth(valid)
+	}
+	return width
+}
+
+// StringWidthE is like StringWidth, but returns an *InvalidUTF8Error if s is
+// not valid UTF-8.
+func (c *Condition) StringWidthE(s string) (int, error) {
+	if err := validUTF8(s); err != nil {
+		return 0, err
+	}
+	return c.StringWidth(s), nil
+}
+
+// TruncateE is like Truncate, but returns an *InvalidUTF8Error if s or tail
+// is not valid UTF-8.
+func (c *Condition) TruncateE(s string, w int, tail string) (string, error) {
+	if err := validUTF8(s); err != nil {
+		return "", err
+	}
+	if err := validUTF8(tail); err != nil {
+		return "", err
+	}
+	return c.Truncate(s, w, tail), nil
+}
+
+// WrapE is like Wrap, but returns an *InvalidUTF8Error if s is not valid
+// UTF-8.
+func (c *Condition) WrapE(s string, w int) (string, error) {
+	if err := validUTF8(s); err != nil {
+		return "", err
+	}
+	return c.Wrap(s, w), nil
+}
+
+// FillLeftE is like FillLeft, but returns an *InvalidUTF8Error if s is not
+// valid UTF-8.
+func (c *Condition) FillLeftE(s string, w int) (string, error) {
+	if err := validUTF8(s); err != nil {
+		return "", err
+	}
+	return c.FillLeft(s, w), nil
+}
+
+// FillRightE is like FillRight, but returns an *InvalidUTF8Error if s is not
+// valid UTF-8.
+func (c *Condition) FillRightE(s string, w int) (string, error) {
+	if err := validUTF8(s); err != nil {
+		return "", err
+	}
+	return c.FillRight(s, w), nil
+}
+
+// StringWidthE is like StringWidth, but returns an *InvalidUTF8Error if s is
+// not valid UTF-8.
+func StringWidthE(s string) (int, error) {
+	return DefaultCondition.StringWidthE(s)
+}
+
+// TruncateE is like Truncate, but returns an *InvalidUTF8Error if s or tail
+// is not valid UTF-8.
+func TruncateE(s string, w int, tail string) (string, error) {
+	return DefaultCondition.TruncateE(s, w, tail)
+}
+
+// WrapE is like Wrap, but returns an *InvalidUTF8Error if s is not valid
+// UTF-8.
+func WrapE(s string, w int) (string, error) {
+	return DefaultCondition.WrapE(s, w)
+}
+
+// FillLeftE is like FillLeft, but returns an *InvalidUTF8Error if s is not
+// valid UTF-8.
+func FillLeftE(s string, w int) (string, error) {
+	return DefaultCondition.FillLeftE(s, w)
+}
+
+// FillRightE is like FillRight, but returns an *InvalidUTF8Error if s is not
+// valid UTF-8.
+func FillRightE(s string, w int) (string, error) {
+	return DefaultCondition.FillRightE(s, w)
+}
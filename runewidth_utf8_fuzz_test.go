@@ -0,0 +1,59 @@
+// +build go1.18
+
+package runewidth
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func countInvalid(s string) int {
+	n := 0
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		if r == utf8.RuneError && size == 1 {
+			n++
+		}
+		s = s[size:]
+	}
+	return n
+}
+
+func FuzzInvalidUTF8(f *testing.F) {
+	for _, tt := range invalidutf8tests {
+		f.Add(tt.in, 3)
+	}
+	f.Add("\x00\x1b[m\u202e\xff", 2)
+	f.Fuzz(func(t *testing.T, s string, w int) {
+		if w < 0 || w > 100 {
+			return
+		}
+		invalid := countInvalid(s)
+		zero := &Condition{InvalidUTF8: InvalidAsZero}
+		repl := &Condition{InvalidUTF8: InvalidAsReplacement}
+		if got, want := repl.StringWidth(s)-zero.StringWidth(s), invalid; got != want {
+			t.Errorf("StringWidth(%q): replacement - zero = %d, want %d", s, got, want)
+		}
+
+		if _, err := StringWidthE(s); (err != nil) != (invalid > 0) {
+			t.Errorf("StringWidthE(%q) = %v with %d invalid bytes", s, err, invalid)
+		}
+
+		for _, c := range []*Condition{{}, zero, repl} {
+			if out := c.Truncate(s, w, ""); !strings.HasPrefix(s, out) {
+				t.Errorf("Truncate(%q, %d) = %q, not a prefix", s, w, out)
+			}
+			if out := c.Wrap(s, w); strings.Replace(out, "\n", "", -1) != strings.Replace(s, "\n", "", -1) {
+				t.Errorf("Wrap(%q, %d) = %q changes the text", s, w, out)
+			}
+			if out, _ := c.StripInvisible(s); countInvalid(out) != invalid {
+				t.Errorf("StripInvisible(%q) = %q changes invalid bytes", s, out)
+			}
+			c.ControlNotation = ControlCaret
+			if out := c.RenderControls(s); countInvalid(out) != invalid {
+				t.Errorf("RenderControls(%q) = %q changes invalid bytes", s, out)
+			}
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package runewidth
+
+import (
+	"testing"
+)
+
+var invalidutf8tests = []struct {
+	in          string
+	runeError   int
+	replacement int
+	zero        int
+	offset      int
+}{
+	{"abc\xff", 5, 4, 3, 3},
+	{"\xff\xfe", 4, 2, 0, 0},
+	{"\xe3\x81", 4, 2, 0, 0},     // truncated "あ"
+	{"あ\xe3\x81い", 8, 6, 4, 3},   // truncated "あ" between others
+	{"\xff\u0301", 2, 1, 0, 0},   // combining mark on an invalid byte
+	{"\xed\xa0\x80", 6, 3, 0, 0}, // surrogate
+	{"\xc0\xaf", 4, 2, 0, 0},     // overlong "/"
+	{"\ufffd", 2, 2, 2, -1},      // a valid U+FFFD
+	{"\xf0\x9f\x98\x80\xff", 4, 3, 2, 4},
+}
+
+func TestInvalidUTF8(t *testing.T) {
+	for _, tt := range invalidutf8tests {
+		for _, p := range []struct {
+			policy InvalidUTF8
+			want   int
+		}{
+			{InvalidAsRuneError, tt.runeError},
+			{InvalidAsReplacement, tt.replacement},
+			{InvalidAsZero, tt.zero},
+		} {
+			c := &Condition{InvalidUTF8: p.policy}
+			if got := c.StringWidth(tt.in); got != p.want {
+				t.Errorf("StringWidth(%q) with policy %d = %d, want %d", tt.in, p.policy, got, p.want)
+			}
+		}
+	}
+}
+
+func TestInvalidUTF8Truncate(t *testing.T) {
+	c := &Condition{InvalidUTF8: InvalidAsReplacement}
+	var tests = []struct {
+		in  string
+		w   int
+		out string
+	}{
+		{"ab\xffcd", 3, "ab\xff"},
+		{"\xe3\x81\xe3\x81", 3, "\xe3\x81\xe3"},
+		{"あ\xffい", 3, "あ\xff"},
+	}
+	for _, tt := range tests {
+		if out := c.Truncate(tt.in, tt.w, ""); out != tt.out {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.in, tt.w, out, tt.out)
+		}
+	}
+}
+
+func TestInvalidUTF8Wrap(t *testing.T) {
+	c := &Condition{InvalidUTF8: InvalidAsReplacement}
+	if out, want := c.Wrap("ab\xffcd\xfe", 3), "ab\xff\ncd\xfe"; out != want {
+		t.Errorf("Wrap(%q, 3) = %q, want %q", "ab\xffcd\xfe", out, want)
+	}
+	c = &Condition{}
+	if out, want := c.Wrap("a\xffb", 2), "a\n\xff\nb"; out != want {
+		t.Errorf("Wrap(%q, 2) = %q, want %q", "a\xffb", out, want)
+	}
+}
+
+func TestInvalidUTF8E(t *testing.T) {
+	for _, tt := range invalidutf8tests {
+		_, err := StringWidthE(tt.in)
+		if tt.offset < 0 {
+			if err != nil {
+				t.Errorf("StringWidthE(%q) = %v, want nil", tt.in, err)
+			}
+			continue
+		}
+		e, ok := err.(*InvalidUTF8Error)
+		if !ok || e.Offset != tt.offset {
+			t.Errorf("StringWidthE(%q) = %v, want offset %d", tt.in, err, tt.offset)
+		}
+		if _, err := TruncateE(tt.in, 1, ""); err == nil {
+			t.Errorf("TruncateE(%q) returned no error", tt.in)
+		}
+		if _, err := WrapE(tt.in, 1); err == nil {
+			t.Errorf("WrapE(%q) returned no error", tt.in)
+		}
+		if _, err := FillLeftE(tt.in, 10); err == nil {
+			t.Errorf("FillLeftE(%q) returned no error", tt.in)
+		}
+		if _, err := FillRightE(tt.in, 10); err == nil {
+			t.Errorf("FillRightE(%q) returned no error", tt.in)
+		}
+	}
+	if _, err := TruncateE("abc", 2, "\xff"); err == nil {
+		t.Errorf("TruncateE with an invalid tail returned no error")
+	}
+	if out, err := TruncateE("あいう", 4, ""); out != "あい" || err != nil {
+		t.Errorf("TruncateE(%q, 4) = %q, %v, want %q, nil", "あいう", out, err, "あい")
+	}
+}
@@ -0,0 +1,100 @@
+package runewidth
+
+import (
+	"unicode/utf8"
+)
+
+// wcwidth returns the width of r as the UTF-8 charmap of the GNU C Library
+// defines it, following localedata/unicode-gen/utf8_gen.py: East Asian Wide
+// and Fullwidth characters are two cells wide, nonspacing and enclosing
+// marks and format characters other than SOFT HYPHEN and the prepended
+// concatenation marks are zero width, and the others are one cell wide.
+func wcwidth(r rune) int {
+	switch {
+	case r == 0:
+		return 0
+	case r < 0x20 || (r >= 0x7F && r < 0xA0):
+		return -1
+	case r < 0 || r > utf8.MaxRune || (r >= 0xD800 && r <= 0xDFFF):
+		return -1
+	case r == 0x2028 || r == 0x2029:
+		return -1
+	case unassigned.contains(r):
+		return -1
+	case r == 0xAD: // SOFT HYPHEN
+		return 1
+	case r >= 0x1160 && r <= 0x11FF, r >= 0xD7B0 && r <= 0xD7FF:
+		// the medial vowels and final consonants of conjoining jamo
+		return 0
+	case r >= 0x3248 && r <= 0x324F, r >= 0x4DC0 && r <= 0x4DFF:
+		// ambiguous or neutral characters which glibc treats as wide
+		return 2
+	case glibcZeroWidth.contains(r):
+		return 0
+	}
+	switch EastAsianWidthOf(r) {
+	case EastAsianWide, EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// Wcwidth returns the number of cells in r like wcwidth(3) of the GNU C
+// Library in a UTF-8 locale: 0 for NUL, -1 for other control characters,
+// line and paragraph separators, surrogates and unassigned code points, 0
+// for combining marks, format characters and the medial vowels and final
+// consonants of conjoining Hangul jamo, 2 for East Asian Wide and Fullwidth
+// characters and 1 otherwise. The widths follow the Unicode Character
+// Database of UnicodeDataVersion, not the table of RuneWidth, so text style
+// emoji such as U+1F441 are one cell wide.
+//
+// The Condition is only consulted for AmbiguousWidth: a non-zero
+// AmbiguousWidth is the width of the East Asian Ambiguous characters which
+// glibc measures as one cell, as in the CJK variant of Markus Kuhn's
+// wcwidth. The zero value and an AmbiguousWidth of 1 match glibc.
+func (c *Condition) Wcwidth(r rune) int {
+	w := wcwidth(r)
+	if w == 1 && c.AmbiguousWidth != 0 && isAmbiguous(r) {
+		return c.AmbiguousWidth
+	}
+	return w
+}
+
+// Wcswidth returns the number of cells in s like wcswidth(3) of the GNU C
+// Library: the sum of Wcwidth of each rune, or -1 if s contains a rune for
+// which Wcwidth returns -1 or is not valid UTF-8. Unlike StringWidth it does
+// not combine grapheme clusters.
+func (c *Condition) Wcswidth(s string) int {
+	return wcswidth(s, c.Wcwidth)
+}
+
+func wcswidth(s string, wcwidth func(rune) int) int {
+	width := 0
+	for i, r := range s {
+		if r == utf8.RuneError {
+			if _, size := utf8.DecodeRuneInString(s[i:]); size == 1 {
+				return -1
+			}
+		}
+		w := wcwidth(r)
+		if w < 0 {
+			return -1
+		}
+		width += w
+	}
+	return width
+}
+
+// Wcwidth returns the number of cells in r like wcwidth(3) of the GNU C
+// Library in a UTF-8 locale. Unlike the other functions of the package it
+// does not depend on DefaultCondition.
+func Wcwidth(r rune) int {
+	return wcwidth(r)
+}
+
+// Wcswidth returns the number of cells in s like wcswidth(3) of the GNU C
+// Library in a UTF-8 locale. Unlike the other functions of the package it
+// does not depend on DefaultCondition.
+func Wcswidth(s string) int {
+	return wcswidth(s, wcwidth)
+}
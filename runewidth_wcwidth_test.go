@@ -0,0 +1,115 @@
+package runewidth
+
+import (
+	"testing"
+)
+
+func TestWcwidth(t *testing.T) {
+	c := &Condition{AmbiguousWidth: 1}
+
+	var tests = []struct {
+		in   rune
+		want int
+	}{
+		{0, 0},
+		{'\x01', -1},
+		{'\t', -1},
+		{'\x7f', -1},
+		{'\u0085', -1},
+		{'a', 1},
+		{'\u00a0', 1},
+		{'\u00ad', 1},
+		{'\u0300', 0},
+		{'\u0378', -1}, // unassigned
+		{'\u0600', 1},  // prepended concatenation marks
+		{'\u06dd', 1},
+		{'\u070f', 1},
+		{'\u1100', 2},
+		{'\u1160', 0},
+		{'\u11a8', 0},
+		{'\ud7b0', 0},
+		{'\ud7fb', 0},
+		{'\ud7fc', -1}, // unassigned
+		{'\u3248', 2},
+		{'\u4dc0', 2},
+		{'\u4dff', 2},
+		{'\u302a', 0}, // wide nonspacing mark
+		{'\ufe0f', 0},
+		{'\u200b', 0},
+		{'\u2028', -1},
+		{'\u2029', -1},
+		{'\u202e', 0},
+		{'☆', 1},
+		{'あ', 2},
+		{'\ufeff', 0},
+		{'\uffff', -1},
+		{'\U0001F600', 2},
+		{'\U0001F441', 1}, // text presentation emoji
+		{'\U0001F3FB', 2},
+		{'\U0001F3FF', 2},
+		{'\ue000', 1}, // private use
+		{'\U000E0001', 0},
+		{0x110000, -1},
+		{-1, -1},
+	}
+
+	for _, tt := range tests {
+		if got := c.Wcwidth(tt.in); got != tt.want {
+			t.Errorf("Wcwidth(%U) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+	for _, tt := range tests {
+		if got := Wcwidth(tt.in); got != tt.want {
+			t.Errorf("Wcwidth(%U) = %d, want %d", tt.in, got, tt.want)
+		}
+		if got := (&Condition{}).Wcwidth(tt.in); got != tt.want {
+			t.Errorf("zero Condition: Wcwidth(%U) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+
+	c = &Condition{AmbiguousWidth: 2}
+	for _, r := range []rune{'☆', '\u00a7', '\ufffd'} {
+		if got := c.Wcwidth(r); got != 2 {
+			t.Errorf("AmbiguousWidth 2: Wcwidth(%U) = %d, want %d", r, got, 2)
+		}
+	}
+	if got := c.Wcwidth('\u0300'); got != 0 {
+		t.Errorf("AmbiguousWidth 2: Wcwidth(%U) = %d, want %d", '\u0300', got, 0)
+	}
+}
+
+func TestWcswidth(t *testing.T) {
+	c := &Condition{AmbiguousWidth: 1}
+
+	var tests = []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"こんにちわ", 10},
+		{"こんにちわ\x00世界", 14},
+		{"a\tb", -1},
+		{"a\nb", -1},
+		{"é", 1},
+		{"👩‍🍳", 4},
+		{"🏳️‍🌈", 3},
+		{"각", 2},
+		{"abc\xff", -1},
+		{"\ufffd", 1},
+	}
+
+	for _, tt := range tests {
+		if got := c.Wcswidth(tt.in); got != tt.want {
+			t.Errorf("Wcswidth(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+	for _, tt := range tests {
+		if got := Wcswidth(tt.in); got != tt.want {
+			t.Errorf("Wcswidth(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+	if got := (&Condition{AmbiguousWidth: 2}).Wcswidth("☆☆"); got != 4 {
+		t.Errorf("AmbiguousWidth 2: Wcswidth(%q) = %d, want %d", "☆☆", got, 4)
+	}
+}
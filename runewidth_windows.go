@@ -26,3 +26,10 @@ func IsEastAsian() bool {
 
 	return false
 }
+
+// isEastAsianEnv reports whether the locale described by the environment
+// variables getenv looks up is CJK. The console code page, not the
+// environment, tells the locale on Windows, so it is always false.
+func isEastAsianEnv(getenv func(string) string) bool {
+	return false
+}
@@ -0,0 +1,130 @@
+// +build ignore
+
+// gentrie reads the interval table from runewidth_table.go on stdin and
+// writes a three-stage lookup trie of the same widths to stdout.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+)
+
+const (
+	leafBits  = 6 // runes per leaf: 1 << leafBits
+	indexBits = 6 // leaves per index block: 1 << indexBits
+)
+
+var reEntry = regexp.MustCompile(`\{\s*(0x[0-9A-Fa-f]+),\s*(0x[0-9A-Fa-f]+),\s*(-?\d+)\}`)
+
+func main() {
+	// Runes outside the table are one cell wide, stored as width+1.
+	widths := make([]byte, utf8.MaxRune+1)
+	for i := range widths {
+		widths[i] = 2
+	}
+
+	s := bufio.NewScanner(os.Stdin)
+	for s.Scan() {
+		m := reEntry.FindStringSubmatch(s.Text())
+		if m == nil {
+			continue
+		}
+		first, _ := strconv.ParseInt(m[1], 0, 32)
+		last, _ := strconv.ParseInt(m[2], 0, 32)
+		width, _ := strconv.Atoi(m[3])
+		for r := first; r <= last; r++ {
+			widths[r] = byte(width + 1)
+		}
+	}
+	if err := s.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Pack four 2-bit values per byte and deduplicate the leaves.
+	const leafSize = 1 << leafBits / 4
+	var leaves []byte
+	leafIndex := map[string]int{}
+	var leafRefs []int
+	for i := 0; i < len(widths); i += 1 << leafBits {
+		leaf := make([]byte, leafSize)
+		for j := 0; j < 1<<leafBits; j++ {
+			leaf[j/4] |= widths[i+j] << (uint(j%4) * 2)
+		}
+		n, ok := leafIndex[string(leaf)]
+		if !ok {
+			n = len(leafIndex)
+			leafIndex[string(leaf)] = n
+			leaves = append(leaves, leaf...)
+		}
+		leafRefs = append(leafRefs, n)
+	}
+
+	// Deduplicate the blocks of leaf references.
+	var index2 []int
+	blockIndex := map[string]int{}
+	var index1 []int
+	for i := 0; i < len(leafRefs); i += 1 << indexBits {
+		block := leafRefs[i : i+1<<indexBits]
+		key := fmt.Sprint(block)
+		n, ok := blockIndex[key]
+		if !ok {
+			n = len(blockIndex)
+			blockIndex[key] = n
+			index2 = append(index2, block...)
+		}
+		index1 = append(index1, n)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "// Code generated by script/gentrie.go; DO NOT EDIT.")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "package runewidth")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "// trieWidth returns wcwidth9_width(r) for 0 <= r <= utf8.MaxRune. It looks")
+	fmt.Fprintf(&b, "// up the block of %d runes, then the leaf of %d runes, then the byte holding\n", 1<<(leafBits+indexBits), 1<<leafBits)
+	fmt.Fprintln(&b, "// the 2-bit width+1 of four runes.")
+	fmt.Fprintln(&b, "func trieWidth(r rune) int {")
+	fmt.Fprintf(&b, "\ti := int(trieIndex1[r>>%d])<<%d | int(r>>%d)&%#x\n", leafBits+indexBits, indexBits, leafBits, 1<<indexBits-1)
+	fmt.Fprintf(&b, "\tj := int(trieIndex2[i])<<%d | int(r>>2)&%#x\n", leafBits-2, leafSize-1)
+	fmt.Fprintln(&b, "\treturn int(trieLeaves[j]>>(uint(r&3)<<1)&3) - 1")
+	fmt.Fprintln(&b, "}")
+	fmt.Fprintln(&b)
+	writeArray(&b, "trieIndex1", index1)
+	writeArray(&b, "trieIndex2", index2)
+	ints := make([]int, len(leaves))
+	for i, v := range leaves {
+		ints[i] = int(v)
+	}
+	writeArray(&b, "trieLeaves", ints)
+
+	out, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	os.Stdout.Write(out)
+}
+
+func writeArray(b *bytes.Buffer, name string, values []int) {
+	typ := "uint8"
+	for _, v := range values {
+		if v > 0xFF {
+			typ = "uint16"
+			break
+		}
+	}
+	fmt.Fprintf(b, "var %s = [%d]%s{", name, len(values), typ)
+	for i, v := range values {
+		if i%16 == 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(b, "%#02x, ", v)
+	}
+	b.WriteString("\n}\n\n")
+}
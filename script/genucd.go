@@ -0,0 +1,129 @@
+// +build ignore
+
+// genucd reads the Unicode Character Database from testdata/ppucd.txt and
+// writes the property tables used by the package to stdout.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-runewidth/internal/ucd"
+)
+
+func main() {
+	f, err := os.Open("testdata/ppucd.txt")
+	if err != nil {
+		log.Fatal(err)
+	}
+	db, err := ucd.Parse(f)
+	f.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "// Code generated by script/genucd.go from testdata/ppucd.txt; DO NOT EDIT.")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "package runewidth")
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "// ucdVersion is the version of the Unicode Character Database the tables\n")
+	fmt.Fprintf(&b, "// below were generated from.\n")
+	fmt.Fprintf(&b, "const ucdVersion = %q\n\n", db.Version)
+
+	writeEastAsianWidths(&b, db)
+	writeRanges(&b, db, "emoji", "Emoji", func(p ucd.Props) bool {
+		return p.Has("Emoji")
+	})
+	writeRanges(&b, db, "emojiPresentation", "Emoji_Presentation", func(p ucd.Props) bool {
+		return p.Has("EPres")
+	})
+	writeRanges(&b, db, "bidiControls", "Bidi_Control", func(p ucd.Props) bool {
+		return p.Has("Bidi_C")
+	})
+	writeRanges(&b, db, "unassigned", "General_Category=Unassigned", func(p ucd.Props) bool {
+		return p["gc"] == "Cn"
+	})
+	writeRanges(&b, db, "glibcZeroWidth", "General_Category=Mn, Me or\n// Cf or Bidi_Class=NSM other than the Prepended_Concatenation_Marks,\n// which the GNU C Library measures as zero width", func(p ucd.Props) bool {
+		switch {
+		case p["gc"] == "Cn" || p.Has("PCM"):
+			return false
+		case p["gc"] == "Mn" || p["gc"] == "Me" || p["gc"] == "Cf":
+			return true
+		}
+		return p["bc"] == "NSM"
+	})
+	writeAges(&b, db, "wideAges", "East_Asian_Width=Wide or Fullwidth", func(p ucd.Props) bool {
+		return p["ea"] == "W" || p["ea"] == "F"
+	})
+
+	out, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	os.Stdout.Write(out)
+}
+
+func writeRanges(b *bytes.Buffer, db *ucd.Database, name, desc string, in func(ucd.Props) bool) {
+	fmt.Fprintf(b, "// %s holds the code points with %s.\n", name, desc)
+	fmt.Fprintf(b, "var %s = rangeTable{\n", name)
+	db.Each(func(p ucd.Props) string {
+		return strconv.FormatBool(in(p))
+	}, func(first, last rune, value string) {
+		if value == "true" {
+			fmt.Fprintf(b, "\t{0x%04X, 0x%04X},\n", first, last)
+		}
+	})
+	fmt.Fprintln(b, "}")
+	fmt.Fprintln(b)
+}
+
+func writeAges(b *bytes.Buffer, db *ucd.Database, name, desc string, in func(ucd.Props) bool) {
+	fmt.Fprintf(b, "// %s holds the Unicode version in which the code points with\n", name)
+	fmt.Fprintf(b, "// %s were assigned.\n", desc)
+	fmt.Fprintf(b, "var %s = ageTable{\n", name)
+	db.Each(func(p ucd.Props) string {
+		if !in(p) || p["age"] == "NA" {
+			return ""
+		}
+		return p["age"]
+	}, func(first, last rune, value string) {
+		if value == "" {
+			return
+		}
+		v := strings.SplitN(value, ".", 2)
+		fmt.Fprintf(b, "\t{0x%04X, 0x%04X, %s, %s},\n", first, last, v[0], v[1])
+	})
+	fmt.Fprintln(b, "}")
+	fmt.Fprintln(b)
+}
+
+var eastAsianWidthNames = map[string]string{
+	"A":  "EastAsianAmbiguous",
+	"H":  "EastAsianHalfwidth",
+	"W":  "EastAsianWide",
+	"F":  "EastAsianFullwidth",
+	"Na": "EastAsianNarrow",
+}
+
+func writeEastAsianWidths(b *bytes.Buffer, db *ucd.Database) {
+	fmt.Fprintln(b, "// eastAsianWidths holds the East_Asian_Width of the code points which are")
+	fmt.Fprintln(b, "// not Neutral.")
+	fmt.Fprintln(b, "var eastAsianWidths = eastAsianWidthTable{")
+	db.Each(func(p ucd.Props) string {
+		return p["ea"]
+	}, func(first, last rune, value string) {
+		if name, ok := eastAsianWidthNames[value]; ok {
+			fmt.Fprintf(b, "\t{0x%04X, 0x%04X, %s},\n", first, last, name)
+		} else if value != "N" {
+			log.Fatalf("unknown East_Asian_Width %q", value)
+		}
+	})
+	fmt.Fprintln(b, "}")
+	fmt.Fprintln(b)
+}
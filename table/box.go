@@ -0,0 +1,149 @@
+package table
+
+import (
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Box draws a frame around a block of text, such as a panel of a TUI. The
+// zero value draws no frame and fits the text, measured with
+// runewidth.DefaultCondition.
+type Box struct {
+	// Condition measures the text and the border. Nil means
+	// runewidth.DefaultCondition.
+	Condition *runewidth.Condition
+
+	// Border is drawn around the text: its Left and Right on every line,
+	// and its Top and Bottom rules above and below. Join and the Header
+	// rule are not used. If a part of the border is wider than one cell
+	// with Condition, as the box-drawing characters are with an
+	// AmbiguousWidth of 2, ASCII is drawn instead.
+	Border Border
+
+	// Width is the width of the box in cells, including the border and the
+	// padding. Zero means as wide as the widest line of the text.
+	Width int
+
+	// Height is the number of lines of the box, including the rules. Lines
+	// of text which do not fit are dropped. Zero means as high as the
+	// text.
+	Height int
+
+	// Padding is the number of spaces between the text and the left and
+	// right of the border. A negative Padding is treated as zero.
+	Padding int
+
+	// Align, Overflow and Tail are applied to the lines of the text as to
+	// the cells of a Column whose MaxWidth is the width inside the box.
+	Align    Align
+	Overflow Overflow
+	Tail     string
+}
+
+// Frame returns s in a box of width cells and height lines drawn with the
+// border and a padding of one space, measured with
+// runewidth.DefaultCondition. Zero width or height fits the box to s.
+func Frame(s string, width, height int, border Border) string {
+	b := Box{Border: border, Width: width, Height: height, Padding: 1}
+	return b.Render(s)
+}
+
+func (b *Box) condition() *runewidth.Condition {
+	if b.Condition != nil {
+		return b.Condition
+	}
+	return runewidth.DefaultCondition
+}
+
+// padding returns Padding, or zero if it is negative.
+func (b *Box) padding() int {
+	if b.Padding < 0 {
+		return 0
+	}
+	return b.Padding
+}
+
+// Render returns s in the box. Each line ends with '\n'.
+func (b *Box) Render(s string) string {
+	c := b.condition()
+	border := b.Border
+	if !border.narrow(c) {
+		border = ASCII
+	}
+
+	frame := c.StringWidth(border.Left) + c.StringWidth(border.Right) + 2*b.padding()
+	inner := 0
+	if b.Width > 0 {
+		inner = b.Width - frame
+		if inner < 0 {
+			inner = 0
+		}
+	}
+
+	var text []string
+	if b.Width > 0 && inner == 0 {
+		text = make([]string, strings.Count(s, "\n")+1)
+	} else {
+		col := Column{MaxWidth: inner, Overflow: b.Overflow, Tail: b.Tail}
+		text = lines(c, s, col)
+	}
+	if b.Width > 0 {
+		// Wrapping keeps a character wider than the box on its own line, so
+		// cut it off.
+		for i, line := range text {
+			if c.StringWidth(line) > inner {
+				text[i] = c.Truncate(line, inner, "")
+			}
+		}
+	} else {
+		for _, line := range text {
+			if w := c.StringWidth(line); w > inner {
+				inner = w
+			}
+		}
+	}
+
+	if b.Height > 0 {
+		h := b.Height
+		if border.Top.Fill != "" {
+			h--
+		}
+		if border.Bottom.Fill != "" {
+			h--
+		}
+		if h < 0 {
+			h = 0
+		}
+		if len(text) > h {
+			text = text[:h]
+		}
+		for len(text) < h {
+			text = append(text, "")
+		}
+	}
+
+	var out strings.Builder
+	pad := strings.Repeat(" ", b.padding())
+	b.rule(&out, border.Top, inner)
+	for _, line := range text {
+		out.WriteString(border.Left)
+		out.WriteString(pad)
+		out.WriteString(align(c, line, inner, b.Align))
+		out.WriteString(pad)
+		out.WriteString(border.Right)
+		out.WriteByte('\n')
+	}
+	b.rule(&out, border.Bottom, inner)
+	return out.String()
+}
+
+func (b *Box) rule(out *strings.Builder, r Rule, inner int) {
+	if r.Fill == "" {
+		return
+	}
+	out.WriteString(r.Left)
+	out.WriteString(fill(b.condition(), r.Fill, inner+2*b.padding()))
+	out.WriteString(r.Right)
+	out.WriteByte('\n')
+}
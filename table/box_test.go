@@ -0,0 +1,87 @@
+package table
+
+import (
+	"testing"
+
+	"github.com/mattn/go-runewidth"
+)
+
+func TestBox(t *testing.T) {
+	narrow := &runewidth.Condition{AmbiguousWidth: 1}
+	wide := &runewidth.Condition{AmbiguousWidth: 2}
+	var tests = []struct {
+		box  Box
+		s    string
+		want string
+	}{
+		{Box{Condition: narrow, Border: Light, Padding: 1}, "東京\nabc", "" +
+			"┌──────┐\n" +
+			"│ 東京 │\n" +
+			"│ abc  │\n" +
+			"└──────┘\n"},
+		{Box{Condition: narrow, Border: Heavy, Width: 10, Height: 5, Padding: 1}, "東京", "" +
+			"┏━━━━━━━━┓\n" +
+			"┃ 東京   ┃\n" +
+			"┃        ┃\n" +
+			"┃        ┃\n" +
+			"┗━━━━━━━━┛\n"},
+		{Box{Condition: narrow, Border: Double, Width: 8, Align: AlignRight}, "あ\nb", "" +
+			"╔══════╗\n" +
+			"║    あ║\n" +
+			"║     b║\n" +
+			"╚══════╝\n"},
+		{Box{Condition: narrow, Border: Rounded, Width: 9, Padding: 1, Align: AlignCenter}, "ab", "" +
+			"╭───────╮\n" +
+			"│  ab   │\n" +
+			"╰───────╯\n"},
+		{Box{Condition: narrow, Border: ASCII, Width: 8, Height: 4, Padding: 1, Overflow: OverflowWrap}, "日本の首都", "" +
+			"+------+\n" +
+			"| 日本 |\n" +
+			"| の首 |\n" +
+			"+------+\n"},
+		{Box{Condition: narrow, Border: Light, Width: 8, Padding: 1, Tail: "~"}, "日本の首都", "" +
+			"┌──────┐\n" +
+			"│ 日~  │\n" +
+			"└──────┘\n"},
+		{Box{Condition: narrow, Border: Light, Width: 5, Padding: 1, Overflow: OverflowWrap}, "あい", "" +
+			"┌───┐\n" +
+			"│   │\n" +
+			"│   │\n" +
+			"└───┘\n"},
+		{Box{Condition: narrow, Border: Light, Width: 2}, "abc", "" +
+			"┌┐\n" +
+			"││\n" +
+			"└┘\n"},
+		{Box{Condition: narrow, Border: Light, Width: 6, Padding: -1}, "東京", "" +
+			"┌────┐\n" +
+			"│東京│\n" +
+			"└────┘\n"},
+		{Box{Condition: wide, Border: Light, Padding: 1}, "東京", "" +
+			"+------+\n" +
+			"| 東京 |\n" +
+			"+------+\n"},
+		{Box{Condition: wide, Border: Plain}, "東京\nx", "" +
+			"東京\n" +
+			"x   \n"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.box.Render(tt.s); got != tt.want {
+			t.Errorf("Render(%q) with %+v = \n%s\nwant\n%s", tt.s, tt.box, got, tt.want)
+		}
+	}
+}
+
+func TestFrame(t *testing.T) {
+	old := runewidth.DefaultCondition
+	defer func() { runewidth.DefaultCondition = old }()
+	runewidth.DefaultCondition = &runewidth.Condition{AmbiguousWidth: 1}
+
+	want := "" +
+		"+------+\n" +
+		"| つの |\n" +
+		"+------+\n"
+	if got := Frame("つの", 0, 0, ASCII); got != want {
+		t.Errorf("Frame() = \n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,158 @@
+package table
+
+import (
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Order is the order in which a Grid puts the items into its columns.
+type Order int
+
+const (
+	// OrderDown fills the columns top to bottom, as ls -C does.
+	OrderDown Order = iota
+	// OrderAcross fills the rows left to right, as ls -x does.
+	OrderAcross
+)
+
+// Grid lays out a list of items, such as file names, in as many columns as
+// fit in a width, as ls and column do. The zero value fills the columns
+// top to bottom without a width limit, measured with
+// runewidth.DefaultCondition.
+type Grid struct {
+	// Condition measures the items. Nil means runewidth.DefaultCondition.
+	Condition *runewidth.Condition
+
+	// Width is the width of the lines in cells. Zero means no limit, so
+	// that the items are laid out in a single line. An item wider than
+	// Width is put in a single column.
+	Width int
+
+	Order Order
+
+	// Gap is the number of spaces between the columns. A negative Gap is
+	// treated as zero.
+	Gap int
+}
+
+// Columnize lays out the items in as many columns as fit in width cells,
+// separated by two spaces and filled in order, measured with
+// runewidth.DefaultCondition.
+func Columnize(items []string, width int, order Order) []string {
+	g := Grid{Width: width, Order: order, Gap: 2}
+	return g.Lines(items)
+}
+
+func (g *Grid) condition() *runewidth.Condition {
+	if g.Condition != nil {
+		return g.Condition
+	}
+	return runewidth.DefaultCondition
+}
+
+// gap returns Gap, or zero if it is negative.
+func (g *Grid) gap() int {
+	if g.Gap < 0 {
+		return 0
+	}
+	return g.Gap
+}
+
+// Lines returns the lines of the items laid out in columns, without
+// trailing spaces or line breaks. The items should not contain line
+// breaks.
+func (g *Grid) Lines(items []string) []string {
+	if len(items) == 0 {
+		return nil
+	}
+	c := g.condition()
+	widths := make([]int, len(items))
+	for i, item := range items {
+		widths[i] = c.StringWidth(item)
+	}
+
+	// No more than (Width+Gap)/(minWidth+Gap) columns fit, as even the
+	// narrowest items take minWidth cells and a gap but the last.
+	gap, max := g.gap(), len(items)
+	if g.Width > 0 {
+		minWidth := widths[0]
+		for _, w := range widths[1:] {
+			if w < minWidth {
+				minWidth = w
+			}
+		}
+		if minWidth+gap > 0 && (g.Width+gap)/(minWidth+gap) < max {
+			max = (g.Width + gap) / (minWidth + gap)
+		}
+		if max < 1 {
+			max = 1
+		}
+	}
+
+	var rows int
+	var cols []int
+	for n := max; n >= 1; n-- {
+		rows, cols = g.layout(widths, n)
+		if g.fits(cols) {
+			break
+		}
+	}
+
+	out := make([]string, rows)
+	for r := range out {
+		var b strings.Builder
+		for k := range cols {
+			i := g.index(r, k, rows, len(cols))
+			if i >= len(items) {
+				continue
+			}
+			if k > 0 {
+				b.WriteString(strings.Repeat(" ", g.gap()))
+			}
+			b.WriteString(items[i])
+			b.WriteString(strings.Repeat(" ", cols[k]-widths[i]))
+		}
+		out[r] = strings.TrimRight(b.String(), " ")
+	}
+	return out
+}
+
+// layout returns the number of rows and the widths of the columns of the
+// items with the given widths laid out in at most n columns.
+func (g *Grid) layout(widths []int, n int) (int, []int) {
+	rows := (len(widths) + n - 1) / n
+	if g.Order == OrderDown {
+		// Filling the columns down may leave fewer of them.
+		n = (len(widths) + rows - 1) / rows
+	}
+	cols := make([]int, n)
+	for r := 0; r < rows; r++ {
+		for k := range cols {
+			if i := g.index(r, k, rows, n); i < len(widths) && widths[i] > cols[k] {
+				cols[k] = widths[i]
+			}
+		}
+	}
+	return rows, cols
+}
+
+// index returns the index of the item in row r and column k of a grid of
+// the given size.
+func (g *Grid) index(r, k, rows, cols int) int {
+	if g.Order == OrderAcross {
+		return r*cols + k
+	}
+	return k*rows + r
+}
+
+func (g *Grid) fits(cols []int) bool {
+	if g.Width <= 0 || len(cols) == 1 {
+		return true
+	}
+	w := g.gap() * (len(cols) - 1)
+	for _, cw := range cols {
+		w += cw
+	}
+	return w <= g.Width
+}
@@ -0,0 +1,89 @@
+package table
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mattn/go-runewidth"
+)
+
+func TestGrid(t *testing.T) {
+	items := []string{"東京.txt", "a.go", "大阪.md", "ｾｶｲ.png", "b", "名古屋.csv", "c.go"}
+	var tests = []struct {
+		width int
+		order Order
+		want  []string
+	}{
+		{0, OrderDown, []string{
+			"東京.txt  a.go  大阪.md  ｾｶｲ.png  b  名古屋.csv  c.go",
+		}},
+		{40, OrderDown, []string{
+			"東京.txt  大阪.md  b           c.go",
+			"a.go      ｾｶｲ.png  名古屋.csv",
+		}},
+		{40, OrderAcross, []string{
+			"東京.txt    a.go  大阪.md  ｾｶｲ.png  b",
+			"名古屋.csv  c.go",
+		}},
+		{24, OrderAcross, []string{
+			"東京.txt  a.go",
+			"大阪.md   ｾｶｲ.png",
+			"b         名古屋.csv",
+			"c.go",
+		}},
+		{10, OrderAcross, []string{
+			"東京.txt", "a.go", "大阪.md", "ｾｶｲ.png", "b", "名古屋.csv", "c.go",
+		}},
+		{3, OrderDown, []string{
+			"東京.txt", "a.go", "大阪.md", "ｾｶｲ.png", "b", "名古屋.csv", "c.go",
+		}},
+	}
+
+	for _, tt := range tests {
+		g := Grid{
+			Condition: &runewidth.Condition{AmbiguousWidth: 1},
+			Width:     tt.width,
+			Order:     tt.order,
+			Gap:       2,
+		}
+		if got := g.Lines(items); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Lines() with width %d, order %d = %q, want %q", tt.width, tt.order, got, tt.want)
+		}
+	}
+}
+
+func TestGridGap(t *testing.T) {
+	var tests = []struct {
+		width, gap int
+		want       []string
+	}{
+		{4, 2, []string{"a  b"}},
+		{3, 2, []string{"a", "b"}},
+		{2, -1, []string{"ab"}},
+		{1, -1, []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		g := Grid{Width: tt.width, Gap: tt.gap}
+		if got := g.Lines([]string{"a", "b"}); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Lines() with width %d, gap %d = %q, want %q", tt.width, tt.gap, got, tt.want)
+		}
+	}
+}
+
+func TestGridEmpty(t *testing.T) {
+	if got := Columnize(nil, 80, OrderDown); got != nil {
+		t.Errorf("Columnize(nil) = %q, want nil", got)
+	}
+}
+
+func TestColumnize(t *testing.T) {
+	got := Columnize([]string{"あ", "い", "う", "え", "お"}, 12, OrderDown)
+	want := []string{
+		"あ  う  お",
+		"い  え",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Columnize() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,372 @@
+// Package table renders rows of cells as a table whose columns line up on a
+// terminal, measuring the cells with a runewidth.Condition so that wide
+// characters, combining marks and emoji take the cells they are displayed
+// in.
+package table
+
+import (
+	"io"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Align is the horizontal alignment of the cells of a column.
+type Align int
+
+const (
+	// AlignLeft pads the cells on the right.
+	AlignLeft Align = iota
+	// AlignRight pads the cells on the left.
+	AlignRight
+	// AlignCenter pads the cells on both sides, one more cell on the right
+	// if the padding is odd.
+	AlignCenter
+)
+
+// Overflow is what a column does with a cell wider than its MaxWidth.
+type Overflow int
+
+const (
+	// OverflowTruncate truncates the lines of the cell and appends the
+	// Tail of the column.
+	OverflowTruncate Overflow = iota
+	// OverflowWrap wraps the lines of the cell.
+	OverflowWrap
+)
+
+// Column configures a column of a Table.
+type Column struct {
+	Align Align
+
+	// MaxWidth is the maximum width of the column in cells. Zero means no
+	// limit.
+	MaxWidth int
+
+	// Overflow is what is done with lines wider than MaxWidth.
+	Overflow Overflow
+
+	// Tail is appended to truncated lines, such as "..." or "…".
+	Tail string
+}
+
+// Rule is a horizontal line of a Border, such as the one below the header.
+// It is drawn only if Fill is not empty.
+type Rule struct {
+	Left, Fill, Join, Right string
+}
+
+// Border is the set of strings a Table draws its frame and the lines
+// between its columns with. Left, Join and Right are drawn on every line
+// of the rows, and the Left, Join and Right of the rules should be as wide
+// as them.
+type Border struct {
+	Left, Join, Right   string
+	Top, Header, Bottom Rule
+}
+
+var (
+	// Plain separates the columns with spaces only.
+	Plain = Border{}
+
+	// Simple underlines the header with dashes.
+	Simple = Border{
+		Join:   " ",
+		Header: Rule{Fill: "-", Join: " "},
+	}
+
+	// ASCII draws the frame with '+', '-' and '|'.
+	ASCII = Border{
+		Left: "|", Join: "|", Right: "|",
+		Top:    Rule{"+", "-", "+", "+"},
+		Header: Rule{"+", "-", "+", "+"},
+		Bottom: Rule{"+", "-", "+", "+"},
+	}
+
+	// Light draws the frame with light box-drawing characters.
+	Light = Border{
+		Left: "│", Join: "│", Right: "│",
+		Top:    Rule{"┌", "─", "┬", "┐"},
+		Header: Rule{"├", "─", "┼", "┤"},
+		Bottom: Rule{"└", "─", "┴", "┘"},
+	}
+
+	// Heavy draws the frame with heavy box-drawing characters.
+	Heavy = Border{
+		Left: "┃", Join: "┃", Right: "┃",
+		Top:    Rule{"┏", "━", "┳", "┓"},
+		Header: Rule{"┣", "━", "╋", "┫"},
+		Bottom: Rule{"┗", "━", "┻", "┛"},
+	}
+
+	// Double draws the frame with double box-drawing characters.
+	Double = Border{
+		Left: "║", Join: "║", Right: "║",
+		Top:    Rule{"╔", "═", "╦", "╗"},
+		Header: Rule{"╠", "═", "╬", "╣"},
+		Bottom: Rule{"╚", "═", "╩", "╝"},
+	}
+
+	// Rounded draws the frame like Light, with rounded corners.
+	Rounded = Border{
+		Left: "│", Join: "│", Right: "│",
+		Top:    Rule{"╭", "─", "┬", "╮"},
+		Header: Rule{"├", "─", "┼", "┤"},
+		Bottom: Rule{"╰", "─", "┴", "╯"},
+	}
+)
+
+// narrow reports whether every part of the border is at most one cell wide
+// with c. The box-drawing characters are ambiguous, so they are two cells
+// wide with an AmbiguousWidth of 2.
+func (b Border) narrow(c *runewidth.Condition) bool {
+	for _, s := range []string{
+		b.Left, b.Join, b.Right,
+		b.Top.Left, b.Top.Fill, b.Top.Join, b.Top.Right,
+		b.Header.Left, b.Header.Fill, b.Header.Join, b.Header.Right,
+		b.Bottom.Left, b.Bottom.Fill, b.Bottom.Join, b.Bottom.Right,
+	} {
+		if c.StringWidth(s) > 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// Table is a table of text cells. The zero value is an empty table without
+// a border, measured with runewidth.DefaultCondition.
+type Table struct {
+	// Condition measures the cells. Nil means runewidth.DefaultCondition.
+	Condition *runewidth.Condition
+
+	// Header is the first row of the table, separated from the other rows
+	// by the Header rule of the Border. Nil means no header.
+	Header []string
+
+	// Columns configures the columns from the left. Columns without an
+	// entry are left aligned and have no maximum width.
+	Columns []Column
+
+	// Border is drawn around and between the columns. If a part of it is
+	// wider than one cell with Condition, as the box-drawing characters are
+	// with an AmbiguousWidth of 2, ASCII is drawn instead.
+	Border Border
+
+	// Padding is the number of spaces between a cell and the line next to
+	// it. The columns of a table with an empty Join are still separated by
+	// Padding spaces on either side. A negative Padding is treated as zero.
+	Padding int
+
+	rows [][]string
+}
+
+// New returns a new Table with the header and a padding of one space.
+func New(header ...string) *Table {
+	t := &Table{Padding: 1}
+	if len(header) > 0 {
+		t.Header = header
+	}
+	return t
+}
+
+// Append adds a row of cells to the table. A cell may span several lines,
+// separated by '\n'.
+func (t *Table) Append(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// String returns the rendered table. Each line ends with '\n'.
+func (t *Table) String() string {
+	var b strings.Builder
+	t.render(&b)
+	return b.String()
+}
+
+// WriteTo writes the rendered table to w.
+func (t *Table) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, t.String())
+	return int64(n), err
+}
+
+func (t *Table) condition() *runewidth.Condition {
+	if t.Condition != nil {
+		return t.Condition
+	}
+	return runewidth.DefaultCondition
+}
+
+func (t *Table) column(i int) Column {
+	if i < len(t.Columns) {
+		return t.Columns[i]
+	}
+	return Column{}
+}
+
+// lines splits cell into its lines, wrapped or truncated to the MaxWidth of
+// its column.
+func lines(c *runewidth.Condition, cell string, col Column) []string {
+	lines := strings.Split(cell, "\n")
+	if col.MaxWidth <= 0 {
+		return lines
+	}
+	var out []string
+	for _, line := range lines {
+		switch {
+		case c.StringWidth(line) <= col.MaxWidth:
+			out = append(out, line)
+		case col.Overflow == OverflowWrap:
+			out = append(out, strings.Split(c.Wrap(line, col.MaxWidth), "\n")...)
+		default:
+			out = append(out, c.Truncate(line, col.MaxWidth, col.Tail))
+		}
+	}
+	return out
+}
+
+func (t *Table) render(b *strings.Builder) {
+	c := t.condition()
+
+	rows := t.rows
+	if t.Header != nil {
+		rows = append([][]string{t.Header}, rows...)
+	}
+	ncols := 0
+	for _, row := range rows {
+		if len(row) > ncols {
+			ncols = len(row)
+		}
+	}
+	if ncols == 0 {
+		return
+	}
+	border := t.Border
+	if !border.narrow(c) {
+		border = ASCII
+	}
+
+	// Split the cells into lines and measure the columns.
+	cells := make([][][]string, len(rows))
+	widths := make([]int, ncols)
+	for i, row := range rows {
+		cells[i] = make([][]string, ncols)
+		for j := range widths {
+			cell := ""
+			if j < len(row) {
+				cell = row[j]
+			}
+			cells[i][j] = lines(c, cell, t.column(j))
+			for _, line := range cells[i][j] {
+				if w := c.StringWidth(line); w > widths[j] {
+					widths[j] = w
+				}
+			}
+		}
+	}
+
+	t.rule(b, border, border.Top, widths)
+	for i := range cells {
+		t.row(b, border, cells[i], widths)
+		if i == 0 && t.Header != nil && len(cells) > 1 {
+			t.rule(b, border, border.Header, widths)
+		}
+	}
+	t.rule(b, border, border.Bottom, widths)
+}
+
+// pads returns the padding on the left and right of column i of n. A
+// negative Padding is no padding.
+func (t *Table) pads(border Border, i, n int) (left, right int) {
+	padding := t.Padding
+	if padding < 0 {
+		padding = 0
+	}
+	if i > 0 || border.Left != "" {
+		left = padding
+	}
+	if i < n-1 || border.Right != "" {
+		right = padding
+	}
+	return left, right
+}
+
+func (t *Table) row(b *strings.Builder, border Border, cells [][]string, widths []int) {
+	c := t.condition()
+	height := 0
+	for _, lines := range cells {
+		if len(lines) > height {
+			height = len(lines)
+		}
+	}
+	for k := 0; k < height; k++ {
+		var line strings.Builder
+		line.WriteString(border.Left)
+		for j, lines := range cells {
+			if j > 0 {
+				line.WriteString(border.Join)
+			}
+			s := ""
+			if k < len(lines) {
+				s = lines[k]
+			}
+			left, right := t.pads(border, j, len(cells))
+			line.WriteString(strings.Repeat(" ", left))
+			line.WriteString(align(c, s, widths[j], t.column(j).Align))
+			line.WriteString(strings.Repeat(" ", right))
+		}
+		line.WriteString(border.Right)
+		s := line.String()
+		if border.Right == "" {
+			s = strings.TrimRight(s, " ")
+		}
+		b.WriteString(s)
+		b.WriteByte('\n')
+	}
+}
+
+func (t *Table) rule(b *strings.Builder, border Border, r Rule, widths []int) {
+	if r.Fill == "" {
+		return
+	}
+	c := t.condition()
+	var line strings.Builder
+	line.WriteString(r.Left)
+	for j, w := range widths {
+		if j > 0 {
+			line.WriteString(r.Join)
+		}
+		left, right := t.pads(border, j, len(widths))
+		line.WriteString(fill(c, r.Fill, left+w+right))
+	}
+	line.WriteString(r.Right)
+	s := line.String()
+	if r.Right == "" {
+		s = strings.TrimRight(s, " ")
+	}
+	b.WriteString(s)
+	b.WriteByte('\n')
+}
+
+// align pads s with spaces to w cells.
+func align(c *runewidth.Condition, s string, w int, a Align) string {
+	switch a {
+	case AlignRight:
+		return c.FillLeft(s, w)
+	case AlignCenter:
+		n := w - c.StringWidth(s)
+		if n <= 0 {
+			return s
+		}
+		return strings.Repeat(" ", n/2) + s + strings.Repeat(" ", n-n/2)
+	}
+	return c.FillRight(s, w)
+}
+
+// fill repeats s to w cells, padding with spaces if the width of s does
+// not divide w.
+func fill(c *runewidth.Condition, s string, w int) string {
+	sw := c.StringWidth(s)
+	if sw <= 0 {
+		return strings.Repeat(" ", w)
+	}
+	return c.FillRight(strings.Repeat(s, w/sw), w)
+}
@@ -0,0 +1,137 @@
+package table
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mattn/go-runewidth"
+)
+
+func TestTable(t *testing.T) {
+	var tests = []struct {
+		border Border
+		want   string
+	}{
+		{Plain, "" +
+			"名前      Size  Note\n" +
+			"東京.txt  1024  日本の首\n" +
+			"                都です\n" +
+			"a.go         3  short\n" +
+			"ｾｶｲ.png     12  line1\n" +
+			"                line2\n"},
+		{Simple, "" +
+			"名前       Size   Note\n" +
+			"--------- ------ ---------\n" +
+			"東京.txt   1024   日本の首\n" +
+			"                  都です\n" +
+			"a.go          3   short\n" +
+			"ｾｶｲ.png      12   line1\n" +
+			"                  line2\n"},
+		{ASCII, "" +
+			"+----------+------+----------+\n" +
+			"| 名前     | Size | Note     |\n" +
+			"+----------+------+----------+\n" +
+			"| 東京.txt | 1024 | 日本の首 |\n" +
+			"|          |      | 都です   |\n" +
+			"| a.go     |    3 | short    |\n" +
+			"| ｾｶｲ.png  |   12 | line1    |\n" +
+			"|          |      | line2    |\n" +
+			"+----------+------+----------+\n"},
+		{Light, "" +
+			"┌──────────┬──────┬──────────┐\n" +
+			"│ 名前     │ Size │ Note     │\n" +
+			"├──────────┼──────┼──────────┤\n" +
+			"│ 東京.txt │ 1024 │ 日本の首 │\n" +
+			"│          │      │ 都です   │\n" +
+			"│ a.go     │    3 │ short    │\n" +
+			"│ ｾｶｲ.png  │   12 │ line1    │\n" +
+			"│          │      │ line2    │\n" +
+			"└──────────┴──────┴──────────┘\n"},
+	}
+
+	for _, tt := range tests {
+		tb := New("名前", "Size", "Note")
+		tb.Condition = &runewidth.Condition{AmbiguousWidth: 1}
+		tb.Border = tt.border
+		tb.Columns = []Column{{}, {Align: AlignRight}, {MaxWidth: 8, Overflow: OverflowWrap}}
+		tb.Append("東京.txt", "1024", "日本の首都です")
+		tb.Append("a.go", "3", "short")
+		tb.Append("ｾｶｲ.png", "12", "line1\nline2")
+		if got := tb.String(); got != tt.want {
+			t.Errorf("String() = \n%s\nwant\n%s", got, tt.want)
+		}
+	}
+}
+
+func TestTableWideBorder(t *testing.T) {
+	for _, border := range []Border{Light, Heavy, Double, Rounded} {
+		tb := New("名前", "Size")
+		tb.Condition = &runewidth.Condition{AmbiguousWidth: 2}
+		tb.Border = border
+		tb.Append("東京.txt", "1024")
+		want := "" +
+			"+----------+------+\n" +
+			"| 名前     | Size |\n" +
+			"+----------+------+\n" +
+			"| 東京.txt | 1024 |\n" +
+			"+----------+------+\n"
+		if got := tb.String(); got != want {
+			t.Errorf("String() = \n%s\nwant\n%s", got, want)
+		}
+	}
+}
+
+func TestTableTruncate(t *testing.T) {
+	tb := New()
+	tb.Border = ASCII
+	tb.Columns = []Column{{MaxWidth: 6, Tail: "~"}, {Align: AlignCenter}}
+	tb.Append("あいうえお", "x")
+	tb.Append("abc", "wide")
+	tb.Append("abcdefgh")
+	want := "" +
+		"+--------+------+\n" +
+		"| あい~  |  x   |\n" +
+		"| abc    | wide |\n" +
+		"| abcde~ |      |\n" +
+		"+--------+------+\n"
+	if got := tb.String(); got != want {
+		t.Errorf("String() = \n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTableEmpty(t *testing.T) {
+	if got := New().String(); got != "" {
+		t.Errorf("String() = %q, want %q", got, "")
+	}
+	var tb Table
+	tb.Append("a", "b")
+	if got, want := tb.String(), "ab\n"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestTableNegativePadding(t *testing.T) {
+	tb := New("名前", "Size")
+	tb.Border = ASCII
+	tb.Padding = -1
+	tb.Append("東京.txt", "1024")
+	want := "" +
+		"+--------+----+\n" +
+		"|名前    |Size|\n" +
+		"+--------+----+\n" +
+		"|東京.txt|1024|\n" +
+		"+--------+----+\n"
+	if got := tb.String(); got != want {
+		t.Errorf("String() = \n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTableWriteTo(t *testing.T) {
+	tb := New("a")
+	tb.Append("あ")
+	var b bytes.Buffer
+	n, err := tb.WriteTo(&b)
+	if err != nil || n != int64(b.Len()) || b.String() != tb.String() {
+		t.Errorf("WriteTo() = %d, %v, wrote %q", n, err, b.String())
+	}
+}
@@ -0,0 +1,71 @@
+package tabwriter_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mattn/go-runewidth"
+	. "github.com/mattn/go-runewidth/tabwriter"
+)
+
+func TestWidth(t *testing.T) {
+	var tests = []struct {
+		cond *runewidth.Condition
+		src  string
+		want string
+	}{
+		{
+			nil,
+			"東京.txt\t1024\tok\n" +
+				"a.go\t3\tok\n" +
+				"ｾｶｲ.png\t12\tok\n" +
+				"👩‍🍳.png\t7\tok\n",
+			"東京.txt  1024  ok\n" +
+				"a.go      3     ok\n" +
+				"ｾｶｲ.png   12    ok\n" +
+				"👩‍🍳.png    7     ok\n",
+		},
+		{
+			&runewidth.Condition{AmbiguousWidth: 1},
+			"☆\tx\n" +
+				"ab\tx\n",
+			"☆   x\n" +
+				"ab  x\n",
+		},
+		{
+			&runewidth.Condition{AmbiguousWidth: 2},
+			"☆\tx\n" +
+				"abc\tx\n",
+			"☆   x\n" +
+				"abc  x\n",
+		},
+		{
+			nil,
+			"\xff本\t\xff\tx\n" +
+				"ab\tx\n",
+			"本\t  x\n" +
+				"ab  x\n",
+		},
+	}
+
+	for _, tt := range tests {
+		var b bytes.Buffer
+		w := NewWriter(&b, 0, 8, 2, ' ', StripEscape)
+		w.Condition = tt.cond
+		w.Write([]byte(tt.src))
+		w.Flush()
+		if got := b.String(); got != tt.want {
+			t.Errorf("%q: got\n%s\nwant\n%s", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestInitKeepsCondition(t *testing.T) {
+	c := &runewidth.Condition{AmbiguousWidth: 2}
+	w := new(Writer)
+	w.Condition = c
+	w.Init(nil, 0, 8, 1, ' ', 0)
+	if w.Condition != c {
+		t.Errorf("Init changed Condition to %+v", w.Condition)
+	}
+}